@@ -11,16 +11,21 @@ import (
 
 func main() {
 	flag.Parse()
-	var flags flag.FlagSet
+	opts := generator.DefaultOptions()
+
 	protogen.Options{
-		ParamFunc: flags.Set,
+		ParamFunc: opts.ParamFunc(),
 	}.Run(func(gen *protogen.Plugin) error {
 		gen.SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
+		// 生成代码之前检查参数和所有 http 绑定，有问题时不输出任何文件
+		if err := generator.Validate(gen, opts); err != nil {
+			return err
+		}
 		for _, f := range gen.Files {
 			if !f.Generate {
 				continue
 			}
-			generator.GenerateFile(gen, f)
+			generator.GenerateFile(gen, f, opts)
 		}
 		return nil
 	})
@@ -0,0 +1,188 @@
+// Package errors 提供业务错误类型，可以直接从服务实现中返回：
+// HTTP 处理函数按错误的状态码和错误码输出响应，gRPC 服务端通过 GRPCStatus 把错误转换为带有
+// errdetails.ErrorInfo 的 gRPC 状态，FromGRPCStatus 再把它还原为业务错误
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"maps"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// BizError 业务错误接口
+type BizError interface {
+	error
+	GetCode() string
+	GetStatus() int
+}
+
+// Error 业务错误实现
+//
+// With 开头的方法返回修改后的副本，不会修改原错误，因此可以基于预定义的错误添加信息
+type Error struct {
+	Code    string
+	Message string
+	Status  int
+
+	// Reason 和 Domain 对应 errdetails.ErrorInfo 的同名字段，Reason 为空时使用 Code
+	Reason string
+	Domain string
+	// Metadata 附加的键值对，对应 errdetails.ErrorInfo.Metadata
+	Metadata map[string]string
+
+	cause   error
+	details []proto.Message
+	code    codes.Code // FromGRPCStatus 还原时的 gRPC 状态码，为 OK 时由 Status 映射
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) GetCode() string {
+	return e.Code
+}
+
+func (e *Error) GetStatus() int {
+	return e.Status
+}
+
+// Unwrap 返回被包装的原始错误
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is 错误码相同的业务错误视为同一错误，用于 errors.Is(err, ErrNotFound)
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// WithReason 设置 ErrorInfo 的 reason
+func (e *Error) WithReason(reason string) *Error {
+	c := e.clone()
+	c.Reason = reason
+	return c
+}
+
+// WithDomain 设置 ErrorInfo 的 domain，通常为服务名，如 user.example.com
+func (e *Error) WithDomain(domain string) *Error {
+	c := e.clone()
+	c.Domain = domain
+	return c
+}
+
+// WithMetadata 添加键值对，kv 按 key1, value1, key2, value2 排列，最后一个没有值的 key 被忽略
+func (e *Error) WithMetadata(kv ...string) *Error {
+	c := e.clone()
+	c.Metadata = maps.Clone(e.Metadata)
+	if c.Metadata == nil {
+		c.Metadata = make(map[string]string, len(kv)/2)
+	}
+	for i := 0; i+1 < len(kv); i += 2 {
+		c.Metadata[kv[i]] = kv[i+1]
+	}
+	return c
+}
+
+// WithDetails 附加 gRPC 状态详情，如 errdetails.BadRequest，转换为 gRPC 状态时放在 ErrorInfo 之后
+func (e *Error) WithDetails(details ...proto.Message) *Error {
+	c := e.clone()
+	c.details = append(append([]proto.Message(nil), e.details...), details...)
+	return c
+}
+
+// Details 返回附加的 gRPC 状态详情，不包括由 Reason、Domain 和 Metadata 生成的 ErrorInfo
+func (e *Error) Details() []proto.Message {
+	return e.details
+}
+
+// WithCause 设置被包装的原始错误，错误信息不变
+func (e *Error) WithCause(cause error) *Error {
+	c := e.clone()
+	c.cause = cause
+	return c
+}
+
+func (e *Error) clone() *Error {
+	c := *e
+	return &c
+}
+
+// New 创建业务错误
+func New(code string, message string, status int) *Error {
+	return &Error{
+		Code:    code,
+		Message: message,
+		Status:  status,
+	}
+}
+
+// Newf 创建格式化的业务错误
+func Newf(code string, status int, format string, args ...interface{}) *Error {
+	return &Error{
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+		Status:  status,
+	}
+}
+
+// 预定义的常用错误，错误码为 HTTP 状态码对应的 gRPC 状态码名称，与 runtime 产生的错误码（如绑定错误的 InvalidArgument）
+// 以及 FromGRPCStatus 还原不带 ErrorInfo 的状态时的错误码一致，因此 errors.Is(err, ErrNotFound) 对 NotFound 状态同样成立
+var (
+	ErrNotFound     = New(codes.NotFound.String(), "资源不存在", 404)
+	ErrInvalidParam = New(codes.InvalidArgument.String(), "参数错误", 400)
+	ErrUnauthorized = New(codes.Unauthenticated.String(), "未授权", 401)
+	ErrForbidden    = New(codes.PermissionDenied.String(), "禁止访问", 403)
+	ErrConflict     = New(codes.AlreadyExists.String(), "资源冲突", 409)
+	ErrInternal     = New(codes.Internal.String(), "内部错误", 500)
+)
+
+// Wrap 包装错误，错误信息取原始错误的信息，原始错误可以通过 errors.Unwrap 和 errors.As 取得。
+// err 为 nil 时返回 nil
+func Wrap(err error, code string, status int) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{
+		Code:    code,
+		Message: err.Error(),
+		Status:  status,
+		cause:   err,
+	}
+}
+
+// FromError 返回错误链中的业务错误；没有业务错误但错误链中有 gRPC 状态时，按 FromGRPCStatus 转换
+func FromError(err error) (*Error, bool) {
+	if err == nil {
+		return nil, false
+	}
+	var e *Error
+	if stderrors.As(err, &e) {
+		return e, true
+	}
+	if s, ok := status.FromError(err); ok {
+		if e := FromGRPCStatus(s); e != nil {
+			return e.WithCause(err), true
+		}
+	}
+	return nil, false
+}
+
+// Is 判断是否是指定的错误（错误码相同），会沿错误链查找
+func Is(err error, target BizError) bool {
+	if err == nil || target == nil {
+		return false
+	}
+
+	var bizErr BizError
+	if !stderrors.As(err, &bizErr) {
+		return false
+	}
+
+	return bizErr.GetCode() == target.GetCode()
+}
@@ -0,0 +1,224 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// roundTrip 模拟经过 gRPC 传输：转换为状态、序列化后再还原
+func roundTrip(t *testing.T, e *Error) (*status.Status, *Error) {
+	t.Helper()
+	b, err := proto.Marshal(e.GRPCStatus().Proto())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pb := &spb.Status{}
+	if err := proto.Unmarshal(b, pb); err != nil {
+		t.Fatal(err)
+	}
+	s := status.FromProto(pb)
+	return s, FromGRPCStatus(s)
+}
+
+func checkError(t *testing.T, got, want *Error) {
+	t.Helper()
+	if got == nil {
+		t.Fatal("got nil error")
+	}
+	if got.Code != want.Code || got.Status != want.Status || got.Message != want.Message ||
+		got.Reason != want.Reason || got.Domain != want.Domain || !reflect.DeepEqual(got.Metadata, want.Metadata) {
+		t.Errorf("got {%q %d %q %q %q %v}, want {%q %d %q %q %q %v}",
+			got.Code, got.Status, got.Message, got.Reason, got.Domain, got.Metadata,
+			want.Code, want.Status, want.Message, want.Reason, want.Domain, want.Metadata)
+	}
+	if len(got.Details()) != len(want.Details()) {
+		t.Fatalf("got %d details, want %d", len(got.Details()), len(want.Details()))
+	}
+	for i := range want.Details() {
+		if !proto.Equal(got.Details()[i], want.Details()[i]) {
+			t.Errorf("detail %d = %v, want %v", i, got.Details()[i], want.Details()[i])
+		}
+	}
+}
+
+func TestGRPCStatusRoundTrip(t *testing.T) {
+	badRequest := &errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "age", Description: "too young"}}}
+	retry := &errdetails.RetryInfo{}
+
+	tests := []struct {
+		name     string
+		err      *Error
+		code     codes.Code
+		metadata map[string]string // 状态中 ErrorInfo 的 metadata
+	}{
+		{
+			name: "business code",
+			err:  New("USER_NOT_FOUND", "user not found", http.StatusNotFound),
+			code: codes.NotFound,
+		},
+		{
+			name:     "reason, domain and metadata",
+			err:      New("USER_NOT_FOUND", "user not found", 404).WithReason("NO_USER").WithDomain("user.example.com").WithMetadata("user_id", "42"),
+			code:     codes.NotFound,
+			metadata: map[string]string{"user_id": "42", MetadataCode: "USER_NOT_FOUND"},
+		},
+		{
+			name: "details",
+			err:  New("INVALID", "bad", 400).WithDetails(badRequest, retry),
+			code: codes.InvalidArgument,
+		},
+		{
+			name:     "status without an exact gRPC mapping",
+			err:      New("TOO_LARGE", "too large", http.StatusRequestEntityTooLarge),
+			code:     codes.ResourceExhausted,
+			metadata: map[string]string{MetadataHTTPStatus: "413"},
+		},
+		{
+			name:     "unmapped status",
+			err:      New("TEAPOT", "teapot", http.StatusTeapot),
+			code:     codes.FailedPrecondition,
+			metadata: map[string]string{MetadataHTTPStatus: "418"},
+		},
+		{
+			name: "predefined",
+			err:  ErrNotFound,
+			code: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, got := roundTrip(t, tt.err)
+			if s.Code() != tt.code || s.Message() != tt.err.Message {
+				t.Errorf("status %v %q, want %v %q", s.Code(), s.Message(), tt.code, tt.err.Message)
+			}
+
+			info, ok := s.Details()[0].(*errdetails.ErrorInfo)
+			if !ok {
+				t.Fatalf("first detail is %T, want *errdetails.ErrorInfo", s.Details()[0])
+			}
+			if len(info.GetMetadata())+len(tt.metadata) > 0 && !reflect.DeepEqual(info.GetMetadata(), tt.metadata) {
+				t.Errorf("ErrorInfo metadata %v, want %v", info.GetMetadata(), tt.metadata)
+			}
+
+			want := tt.err
+			if want.Reason == "" {
+				// 还原时 Reason 为 ErrorInfo 的 reason，即 Code
+				want = want.WithReason(want.Code)
+			}
+			checkError(t, got, want)
+
+			// 还原的错误再次转换时保持相同的状态（Any 中 map 的序列化顺序不固定，比较还原后的错误）
+			s2, again := roundTrip(t, got)
+			if s2.Code() != s.Code() {
+				t.Errorf("second conversion code %v, want %v", s2.Code(), s.Code())
+			}
+			checkError(t, again, want)
+		})
+	}
+}
+
+func TestFromGRPCStatusWithoutErrorInfo(t *testing.T) {
+	bad := &errdetails.BadRequest{}
+	s, err := status.New(codes.NotFound, "missing").WithDetails(bad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := FromGRPCStatus(s)
+	checkError(t, got, New("NotFound", "missing", 404).WithDetails(bad))
+	if !Is(got, ErrNotFound) {
+		t.Error("NotFound status is not ErrNotFound")
+	}
+
+	// codes.Unknown 映射为 500，再次转换时保持原状态码
+	if got := FromGRPCStatus(status.New(codes.Unknown, "boom")).GRPCStatus(); got.Code() != codes.Unknown {
+		t.Errorf("code %v, want Unknown", got.Code())
+	}
+
+	if FromGRPCStatus(nil) != nil || FromGRPCStatus(status.New(codes.OK, "")) != nil {
+		t.Error("FromGRPCStatus of nil or OK status is not nil")
+	}
+}
+
+func TestWrap(t *testing.T) {
+	if err := Wrap(nil, "X", 500); err != nil {
+		t.Fatalf("Wrap(nil) = %v, want nil", err)
+	}
+
+	cause := fmt.Errorf("query: %w", stderrors.New("db down"))
+	err := Wrap(cause, "DB", 503)
+	if err.Error() != cause.Error() || !stderrors.Is(err, cause) {
+		t.Errorf("Wrap lost the cause: %v", err)
+	}
+	var e *Error
+	if !stderrors.As(err, &e) || e.Code != "DB" || e.Status != 503 {
+		t.Errorf("Wrap = %+v", e)
+	}
+}
+
+func TestWithClones(t *testing.T) {
+	base := New("BASE", "base", 400).WithMetadata("a", "1")
+	derived := base.WithReason("R").WithDomain("d").WithMetadata("b", "2", "dangling").WithDetails(&errdetails.RetryInfo{}).WithCause(stderrors.New("c"))
+
+	if base.Reason != "" || base.Domain != "" || len(base.Metadata) != 1 || len(base.Details()) != 0 || base.Unwrap() != nil {
+		t.Errorf("With* modified the original error: %+v", base)
+	}
+	if !reflect.DeepEqual(derived.Metadata, map[string]string{"a": "1", "b": "2"}) {
+		t.Errorf("metadata = %v", derived.Metadata)
+	}
+	if derived.Reason != "R" || derived.Domain != "d" || len(derived.Details()) != 1 || derived.Unwrap() == nil {
+		t.Errorf("derived = %+v", derived)
+	}
+	if !stderrors.Is(derived, base) {
+		t.Error("errors with the same code are not equal")
+	}
+
+	d1 := base.WithDetails(&errdetails.RetryInfo{})
+	d2 := base.WithDetails(&errdetails.BadRequest{})
+	if len(d1.Details()) != 1 || len(d2.Details()) != 1 {
+		t.Error("WithDetails shares the details slice between copies")
+	}
+}
+
+func TestFromError(t *testing.T) {
+	if e, ok := FromError(nil); ok || e != nil {
+		t.Error("FromError(nil) returned an error")
+	}
+
+	biz := New("X", "x", 409)
+	if e, ok := FromError(fmt.Errorf("wrap: %w", biz)); !ok || e != biz {
+		t.Errorf("FromError did not find the wrapped *Error: %v", e)
+	}
+
+	grpcErr := biz.WithDomain("d").GRPCStatus().Err()
+	e, ok := FromError(grpcErr)
+	if !ok {
+		t.Fatal("FromError did not convert the gRPC status")
+	}
+	checkError(t, e, biz.WithReason("X").WithDomain("d"))
+	if e.Unwrap() != grpcErr {
+		t.Errorf("cause = %v, want the gRPC error", e.Unwrap())
+	}
+
+	if _, ok := FromError(stderrors.New("plain")); ok {
+		t.Error("FromError converted a plain error")
+	}
+}
+
+func TestIs(t *testing.T) {
+	err := fmt.Errorf("get user: %w", ErrNotFound.WithMetadata("id", "1"))
+	if !Is(err, ErrNotFound) || !stderrors.Is(err, ErrNotFound) {
+		t.Error("wrapped ErrNotFound is not ErrNotFound")
+	}
+	if Is(err, ErrConflict) || Is(nil, ErrNotFound) || Is(err, nil) {
+		t.Error("Is matched a different error")
+	}
+}
@@ -0,0 +1,88 @@
+package errors
+
+import (
+	"context"
+	stderrors "errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor 把服务实现返回的业务错误（包括被包装的错误和其他 BizError 实现）转换为 gRPC 状态，
+// 错误码、错误信息和 HTTP 状态码通过 ErrorInfo 传递给调用方
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return nil, toGRPCError(err)
+		}
+		return resp, nil
+	}
+}
+
+// StreamServerInterceptor 流式方法的 UnaryServerInterceptor
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return toGRPCError(handler(srv, ss))
+	}
+}
+
+// UnaryClientInterceptor 把调用返回的 gRPC 错误还原为 *Error，见 FromGRPCStatus。
+// 还原后的错误仍然实现 GRPCStatus，status.Code 返回原状态码
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return fromGRPCError(invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+// StreamClientInterceptor 流式方法的 UnaryClientInterceptor，建立流以及收发消息的错误都会被还原，io.EOF 保持不变
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, fromGRPCError(err)
+		}
+		return &clientStream{ClientStream: cs}, nil
+	}
+}
+
+type clientStream struct {
+	grpc.ClientStream
+}
+
+func (s *clientStream) SendMsg(m any) error {
+	return fromGRPCError(s.ClientStream.SendMsg(m))
+}
+
+func (s *clientStream) RecvMsg(m any) error {
+	return fromGRPCError(s.ClientStream.RecvMsg(m))
+}
+
+func (s *clientStream) CloseSend() error {
+	return fromGRPCError(s.ClientStream.CloseSend())
+}
+
+// toGRPCError 把错误链中的业务错误转换为 gRPC 状态错误，错误信息为业务错误本身的信息；其他错误原样返回。
+// 实现了 GRPCStatus 的业务错误（如 runtime.BindError）使用其自身的状态，保留其中的详情
+func toGRPCError(err error) error {
+	var b BizError
+	if err == nil || !stderrors.As(err, &b) {
+		return err
+	}
+	if gs, ok := b.(interface{ GRPCStatus() *status.Status }); ok {
+		if s := gs.GRPCStatus(); s.Code() != codes.OK {
+			return s.Err()
+		}
+	}
+	return New(b.GetCode(), b.Error(), b.GetStatus()).GRPCStatus().Err()
+}
+
+// fromGRPCError 把 gRPC 状态错误还原为 *Error，其他错误（如 io.EOF）原样返回
+func fromGRPCError(err error) error {
+	s, ok := status.FromError(err)
+	if err == nil || !ok || s.Code() == codes.OK {
+		return err
+	}
+	return FromGRPCStatus(s).WithCause(err)
+}
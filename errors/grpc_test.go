@@ -0,0 +1,204 @@
+package errors
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// echoService 只有一个一元方法和一个服务端流式方法的测试服务，两个方法都返回 err；
+// err 为 nil 时一元方法原样返回请求，流式方法发送一条消息后结束
+func echoService(err error) *grpc.ServiceDesc {
+	return &grpc.ServiceDesc{
+		ServiceName: "protogin.test.Echo",
+		HandlerType: (*any)(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "Unary",
+			Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+				in := &wrapperspb.StringValue{}
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+				handler := func(ctx context.Context, req any) (any, error) {
+					if err != nil {
+						return nil, err
+					}
+					return req, nil
+				}
+				info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/protogin.test.Echo/Unary"}
+				return interceptor(ctx, in, info, handler)
+			},
+		}},
+		Streams: []grpc.StreamDesc{{
+			StreamName:    "Stream",
+			ServerStreams: true,
+			Handler: func(srv any, ss grpc.ServerStream) error {
+				in := &wrapperspb.StringValue{}
+				if err := ss.RecvMsg(in); err != nil {
+					return err
+				}
+				if err != nil {
+					return err
+				}
+				return ss.SendMsg(in)
+			},
+		}},
+	}
+}
+
+// dialEcho 启动注册了服务端拦截器的 echoService，返回注册了客户端拦截器的连接
+func dialEcho(t *testing.T, err error) *grpc.ClientConn {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(StreamServerInterceptor()),
+	)
+	srv.RegisterService(echoService(err), struct{}{})
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, dialErr := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(StreamClientInterceptor()),
+	)
+	if dialErr != nil {
+		t.Fatal(dialErr)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func callUnary(conn *grpc.ClientConn) error {
+	return conn.Invoke(context.Background(), "/protogin.test.Echo/Unary", wrapperspb.String("hi"), &wrapperspb.StringValue{})
+}
+
+func callStream(conn *grpc.ClientConn) error {
+	desc := &grpc.StreamDesc{StreamName: "Stream", ServerStreams: true}
+	cs, err := conn.NewStream(context.Background(), desc, "/protogin.test.Echo/Stream")
+	if err != nil {
+		return err
+	}
+	if err := cs.SendMsg(wrapperspb.String("hi")); err != nil {
+		return err
+	}
+	if err := cs.CloseSend(); err != nil {
+		return err
+	}
+	for {
+		if err := cs.RecvMsg(&wrapperspb.StringValue{}); err != nil {
+			return err
+		}
+	}
+}
+
+// plainBizError 没有实现 GRPCStatus 的 BizError
+type plainBizError struct{}
+
+func (plainBizError) Error() string   { return "quota exceeded" }
+func (plainBizError) GetCode() string { return "QUOTA" }
+func (plainBizError) GetStatus() int  { return http.StatusTooManyRequests }
+
+// detailedBizError 自己实现 GRPCStatus 并带有详情的 BizError，如 runtime.BindError
+type detailedBizError struct{}
+
+func (detailedBizError) Error() string   { return "invalid age" }
+func (detailedBizError) GetCode() string { return "InvalidArgument" }
+func (detailedBizError) GetStatus() int  { return http.StatusBadRequest }
+func (e detailedBizError) GRPCStatus() *status.Status {
+	return New(e.GetCode(), e.Error(), e.GetStatus()).WithDetails(badAge).GRPCStatus()
+}
+
+var badAge = &errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "age", Description: "too young"}}}
+
+func TestInterceptors(t *testing.T) {
+	biz := New("USER_NOT_FOUND", "user not found", http.StatusNotFound).WithDomain("user.example.com").WithMetadata("user_id", "42")
+
+	tests := []struct {
+		name    string
+		err     error
+		code    codes.Code
+		want    *Error
+		details []proto.Message
+	}{
+		{
+			name: "business error",
+			err:  biz,
+			code: codes.NotFound,
+			want: biz.WithReason(biz.Code),
+		},
+		{
+			name: "wrapped business error",
+			err:  fmt.Errorf("get user: %w", biz),
+			code: codes.NotFound,
+			want: biz.WithReason(biz.Code),
+		},
+		{
+			name: "BizError implementation",
+			err:  plainBizError{},
+			code: codes.ResourceExhausted,
+			want: New("QUOTA", "quota exceeded", http.StatusTooManyRequests).WithReason("QUOTA"),
+		},
+		{
+			name:    "BizError with its own GRPCStatus details",
+			err:     fmt.Errorf("bind: %w", detailedBizError{}),
+			code:    codes.InvalidArgument,
+			want:    New("InvalidArgument", "invalid age", http.StatusBadRequest).WithReason("InvalidArgument"),
+			details: []proto.Message{badAge},
+		},
+		{
+			name: "gRPC status",
+			err:  status.Error(codes.Unavailable, "try later"),
+			code: codes.Unavailable,
+			want: New("Unavailable", "try later", http.StatusServiceUnavailable),
+		},
+		{
+			name: "plain error",
+			err:  stderrors.New("boom"),
+			code: codes.Unknown,
+			want: New("Unknown", "boom", http.StatusInternalServerError),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := dialEcho(t, tt.err)
+			for kind, call := range map[string]func(*grpc.ClientConn) error{"unary": callUnary, "stream": callStream} {
+				err := call(conn)
+				var e *Error
+				if !stderrors.As(err, &e) {
+					t.Fatalf("%s: error %v (%T), want *Error", kind, err, err)
+				}
+				if status.Code(err) != tt.code {
+					t.Errorf("%s: status code %v, want %v", kind, status.Code(err), tt.code)
+				}
+				checkError(t, e, tt.want.WithDetails(tt.details...))
+			}
+		})
+	}
+}
+
+func TestInterceptorsSuccess(t *testing.T) {
+	conn := dialEcho(t, nil)
+	if err := callUnary(conn); err != nil {
+		t.Errorf("unary: %v", err)
+	}
+	// 流正常结束时 io.EOF 保持不变
+	if err := callStream(conn); err != io.EOF {
+		t.Errorf("stream: %v, want io.EOF", err)
+	}
+}
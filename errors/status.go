@@ -0,0 +1,180 @@
+package errors
+
+import (
+	"net/http"
+	"strconv"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// ErrorInfo.Metadata 中保存业务错误码和 HTTP 状态码的键，只在无法从 reason 和 gRPC 状态码还原时写入，
+// FromGRPCStatus 读取后从 Metadata 中移除
+const (
+	MetadataCode       = "protogin_code"
+	MetadataHTTPStatus = "protogin_http_status"
+)
+
+// GRPCStatus 把业务错误转换为 gRPC 状态，grpc-go 和 status.FromError 通过该方法识别错误
+//
+// 状态码由 HTTP 状态码映射得到（由 FromGRPCStatus 还原的错误保留原状态码），第一条详情为 errdetails.ErrorInfo（reason 为 Reason 或 Code），
+// 其后是 WithDetails 附加的详情
+func (e *Error) GRPCStatus() *status.Status {
+	code := e.code
+	if code == codes.OK {
+		code = CodeFromHTTPStatus(e.Status)
+	}
+	if code == codes.OK {
+		code = codes.Unknown
+	}
+	info := &errdetails.ErrorInfo{
+		Reason:   e.Reason,
+		Domain:   e.Domain,
+		Metadata: make(map[string]string, len(e.Metadata)+2),
+	}
+	for k, v := range e.Metadata {
+		info.Metadata[k] = v
+	}
+	if info.Reason == "" {
+		info.Reason = e.Code
+	} else if info.Reason != e.Code {
+		info.Metadata[MetadataCode] = e.Code
+	}
+	if HTTPStatusFromCode(code) != e.Status {
+		info.Metadata[MetadataHTTPStatus] = strconv.Itoa(e.Status)
+	}
+
+	pb := status.New(code, e.Message).Proto()
+	for _, d := range append([]proto.Message{info}, e.details...) {
+		a, ok := d.(*anypb.Any)
+		if !ok {
+			var err error
+			if a, err = anypb.New(d); err != nil {
+				continue
+			}
+		}
+		pb.Details = append(pb.Details, a)
+	}
+	return status.FromProto(pb)
+}
+
+// FromGRPCStatus 把 gRPC 状态还原为业务错误，s 为 nil 或状态码为 OK 时返回 nil
+//
+// 带有 ErrorInfo 时还原 GRPCStatus 写入的错误码、HTTP 状态码、reason、domain 和 metadata；
+// 否则错误码为 gRPC 状态码的名称（如 NotFound），HTTP 状态码由 gRPC 状态码映射得到。
+// 其余详情可以通过 Details 取得，无法解析的详情保留为 *anypb.Any
+func FromGRPCStatus(s *status.Status) *Error {
+	if s == nil || s.Code() == codes.OK {
+		return nil
+	}
+	e := &Error{
+		Code:    s.Code().String(),
+		Message: s.Message(),
+		Status:  HTTPStatusFromCode(s.Code()),
+		code:    s.Code(),
+	}
+
+	found := false
+	for _, a := range s.Proto().GetDetails() {
+		d, err := a.UnmarshalNew()
+		if err != nil {
+			e.details = append(e.details, a)
+			continue
+		}
+		info, ok := d.(*errdetails.ErrorInfo)
+		if !ok || found {
+			e.details = append(e.details, d)
+			continue
+		}
+		found = true
+		e.Code, e.Reason, e.Domain = info.GetReason(), info.GetReason(), info.GetDomain()
+		for k, v := range info.GetMetadata() {
+			switch k {
+			case MetadataCode:
+				e.Code = v
+			case MetadataHTTPStatus:
+				if n, err := strconv.Atoi(v); err == nil {
+					e.Status = n
+				}
+			default:
+				if e.Metadata == nil {
+					e.Metadata = make(map[string]string)
+				}
+				e.Metadata[k] = v
+			}
+		}
+	}
+	return e
+}
+
+// CodeFromHTTPStatus 将 HTTP 状态码转换为 gRPC 状态码，除 413 和 415 外与 HTTPStatusFromCode 互逆：
+// 未列出的 2xx 为 OK，4xx 为 FailedPrecondition，其余为 Internal
+func CodeFromHTTPStatus(s int) codes.Code {
+	switch s {
+	case http.StatusOK:
+		return codes.OK
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusRequestEntityTooLarge:
+		return codes.ResourceExhausted
+	case http.StatusUnsupportedMediaType:
+		return codes.InvalidArgument
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case 499:
+		return codes.Canceled
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+	switch {
+	case s >= 200 && s < 300:
+		return codes.OK
+	case s >= 400 && s < 500:
+		return codes.FailedPrecondition
+	}
+	return codes.Internal
+}
+
+// HTTPStatusFromCode 将 gRPC 状态码转换为 HTTP 状态码
+func HTTPStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.Canceled:
+		return 499 // Client Closed Request
+	}
+	return http.StatusInternalServerError
+}
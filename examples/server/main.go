@@ -2,17 +2,25 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"log"
 	"net"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"google.golang.org/genproto/googleapis/api/httpbody"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
 
+	"github.com/JarrettGuo/protogin/errors"
 	apiv1 "github.com/JarrettGuo/protogin/gen/api/v1"
-	"github.com/JarrettGuo/protogin/internal/errors"
+	"github.com/JarrettGuo/protogin/gen/protogin"
+	"github.com/JarrettGuo/protogin/runtime"
 )
 
 // 实现服务接口
@@ -121,6 +129,20 @@ func (s *demoServer) UpdateUser(ctx context.Context, req *apiv1.UpdateUserReques
 	}, nil
 }
 
+func (s *demoServer) PatchUser(ctx context.Context, req *apiv1.PatchUserRequest) (*apiv1.User, error) {
+	log.Printf("PatchUser called for ID: %s, validate_only=%v", req.GetUser().GetUserId(), req.ValidateOnly)
+
+	if req.GetUser().GetUserId() == "404" {
+		if s.mode == "monolithic" {
+			return nil, errors.New("USER_NOT_FOUND", "用户不存在", 404)
+		} else {
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+	}
+
+	return req.User, nil
+}
+
 func (s *demoServer) DeleteUser(ctx context.Context, req *apiv1.DeleteUserRequest) (*apiv1.DeleteUserResponse, error) {
 	log.Printf("DeleteUser called for ID: %s", req.UserId)
 
@@ -186,12 +208,14 @@ func (s *demoServer) ListUsers(ctx context.Context, req *apiv1.ListUsersRequest)
 				Name:   "Alice",
 				Email:  "alice@example.com",
 				Age:    30,
+				Phone:  "13800000001", // HTTP 响应中会被清除
 			},
 			{
 				UserId: "user_2",
 				Name:   "Bob",
 				Email:  "bob@example.com",
 				Age:    25,
+				Phone:  "13800000002",
 			},
 		},
 		Total:    2,
@@ -200,6 +224,197 @@ func (s *demoServer) ListUsers(ctx context.Context, req *apiv1.ListUsersRequest)
 	}, nil
 }
 
+func (s *demoServer) BanUser(ctx context.Context, req *apiv1.BanUserRequest) (*apiv1.BanUserResponse, error) {
+	log.Printf("BanUser called for ID: %s, reason=%s, days=%d, operator=%s", req.UserId, req.Reason, req.Days, req.Operator)
+
+	if req.UserId == "admin" {
+		if s.mode == "monolithic" {
+			return nil, errors.New("FORBIDDEN", "不能封禁管理员账户", 403)
+		} else {
+			return nil, status.Error(codes.PermissionDenied, "cannot ban admin user")
+		}
+	}
+
+	return &apiv1.BanUserResponse{Success: true}, nil
+}
+
+func (s *demoServer) SearchUsers(ctx context.Context, req *apiv1.SearchUsersRequest) (*apiv1.ListUsersResponse, error) {
+	log.Printf("SearchUsers called: query=%s, limit=%d", req.Query, req.Limit)
+
+	return &apiv1.ListUsersResponse{
+		Users: []*apiv1.User{
+			{
+				UserId: "user_1",
+				Name:   "Alice",
+				Email:  "alice@example.com",
+				Age:    30,
+			},
+		},
+		Total: 1,
+	}, nil
+}
+
+func (s *demoServer) GetOrgUser(ctx context.Context, req *apiv1.GetOrgUserRequest) (*apiv1.GetUserResponse, error) {
+	log.Printf("GetOrgUser called: org=%s, user=%s", req.GetOrg().GetId(), req.GetUser().GetUserId())
+
+	if req.GetOrg().GetId() == "404" {
+		if s.mode == "monolithic" {
+			return nil, errors.New("ORG_NOT_FOUND", "组织不存在", 404)
+		} else {
+			return nil, status.Error(codes.NotFound, "org not found")
+		}
+	}
+
+	return &apiv1.GetUserResponse{
+		UserId: req.GetUser().GetUserId(),
+		Name:   "Org User",
+		Email:  "member@example.com",
+		Age:    28,
+	}, nil
+}
+
+func (s *demoServer) GetBook(ctx context.Context, req *apiv1.GetBookRequest) (*apiv1.Book, error) {
+	log.Printf("GetBook called: %s", req.Name)
+
+	return &apiv1.Book{
+		Name:  req.Name,
+		Title: "The Go Programming Language",
+	}, nil
+}
+
+func (s *demoServer) ListPosts(ctx context.Context, req *apiv1.ListPostsRequest) (*apiv1.ListPostsResponse, error) {
+	log.Printf("ListPosts called: author=%d, status=%s", req.AuthorId, req.Status)
+
+	return &apiv1.ListPostsResponse{
+		Posts: []*apiv1.Post{
+			{
+				Id:       1,
+				AuthorId: req.AuthorId,
+				Title:    "Hello protogin",
+				Status:   req.Status,
+			},
+		},
+	}, nil
+}
+
+func (s *demoServer) WatchUsers(req *apiv1.WatchUsersRequest, stream grpc.ServerStreamingServer[apiv1.User]) error {
+	log.Printf("WatchUsers called: count=%d", req.Count)
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for i := int32(1); req.Count == 0 || i <= req.Count; i++ {
+		user := &apiv1.User{
+			UserId: fmt.Sprintf("user_%d", i),
+			Name:   fmt.Sprintf("User %d", i),
+			Phone:  "13800000000", // HTTP 响应中会被清除
+		}
+		if err := stream.Send(user); err != nil {
+			return err
+		}
+
+		select {
+		case <-stream.Context().Done():
+			// 客户端断开连接
+			return nil
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+func (s *demoServer) ImportUsers(stream grpc.ClientStreamingServer[apiv1.CreateUserRequest, apiv1.ImportUsersResponse]) error {
+	resp := &apiv1.ImportUsersResponse{}
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if req.Name == "" {
+			return status.Errorf(codes.InvalidArgument, "user %d: name is required", resp.Imported+1)
+		}
+
+		resp.Imported++
+		resp.UserIds = append(resp.UserIds, fmt.Sprintf("user_%d", resp.Imported))
+	}
+
+	log.Printf("ImportUsers called: imported %d users", resp.Imported)
+	return stream.SendAndClose(resp)
+}
+
+func (s *demoServer) UploadAvatar(ctx context.Context, req *apiv1.UploadAvatarRequest) (*apiv1.UploadAvatarResponse, error) {
+	log.Printf("UploadAvatar called for ID: %s, file=%s", req.UserId, req.GetAvatar().GetFilename())
+
+	if req.Avatar == nil {
+		return nil, status.Error(codes.InvalidArgument, "avatar is required")
+	}
+	if !strings.HasPrefix(req.Avatar.ContentType, "image/") {
+		return nil, status.Errorf(codes.InvalidArgument, "avatar must be an image, got %q", req.Avatar.ContentType)
+	}
+
+	return &apiv1.UploadAvatarResponse{
+		Filename:    req.Avatar.Filename,
+		ContentType: req.Avatar.ContentType,
+		Size:        req.Avatar.Size,
+	}, nil
+}
+
+func (s *demoServer) ReplaceAvatar(ctx context.Context, req *apiv1.ReplaceAvatarRequest) (*apiv1.UploadAvatarResponse, error) {
+	log.Printf("ReplaceAvatar called for ID: %s, content_type=%s", req.UserId, req.GetContent().GetContentType())
+
+	if !strings.HasPrefix(req.GetContent().GetContentType(), "image/") {
+		return nil, status.Errorf(codes.InvalidArgument, "avatar must be an image, got %q", req.GetContent().GetContentType())
+	}
+
+	return &apiv1.UploadAvatarResponse{
+		ContentType: req.Content.ContentType,
+		Size:        int64(len(req.Content.Data)),
+	}, nil
+}
+
+func (s *demoServer) ExportUsers(ctx context.Context, req *apiv1.ExportUsersRequest) (*httpbody.HttpBody, error) {
+	log.Printf("ExportUsers called: inline=%v", req.Inline)
+
+	var csv strings.Builder
+	csv.WriteString("user_id,name,email\n")
+	for i := 1; i <= 3; i++ {
+		fmt.Fprintf(&csv, "user_%d,User %d,user%d@example.com\n", i, i, i)
+	}
+
+	disposition, err := anypb.New(&protogin.ContentDisposition{Filename: "users.csv", Inline: req.Inline})
+	if err != nil {
+		return nil, err
+	}
+	return &httpbody.HttpBody{
+		ContentType: "text/csv; charset=utf-8",
+		Data:        []byte(csv.String()),
+		Extensions:  []*anypb.Any{disposition},
+	}, nil
+}
+
+func (s *demoServer) Chat(stream grpc.BidiStreamingServer[apiv1.ChatMessage, apiv1.ChatMessage]) error {
+	log.Printf("Chat called")
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if msg.Text == "" {
+			return status.Error(codes.InvalidArgument, "text is required")
+		}
+
+		if err := stream.Send(&apiv1.ChatMessage{UserId: "bot", Text: "echo: " + msg.Text}); err != nil {
+			return err
+		}
+	}
+}
+
 func (s *demoServer) BatchOperation(ctx context.Context, req *apiv1.BatchRequest) (*apiv1.BatchResponse, error) {
 	log.Printf("BatchOperation called: %s on %d items", req.Operation, len(req.Ids))
 
@@ -276,20 +491,10 @@ func startGRPCServer(srv *demoServer) {
 		log.Fatal(err)
 	}
 
-	// gRPC 拦截器：将业务错误转换为 gRPC 错误
-	interceptor := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		resp, err := handler(ctx, req)
-		if err != nil {
-			// 如果是业务错误，转换为 gRPC 错误
-			if bizErr, ok := err.(errors.BizError); ok {
-				code := httpStatusToGRPCCode(bizErr.GetStatus())
-				return nil, status.Error(code, bizErr.Error())
-			}
-		}
-		return resp, err
-	}
-
-	s := grpc.NewServer(grpc.UnaryInterceptor(interceptor))
+	// gRPC 拦截器：将业务错误转换为带有 ErrorInfo 的 gRPC 错误
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(errors.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(errors.StreamServerInterceptor()))
 	apiv1.RegisterDemoServiceServer(s, srv)
 
 	log.Println("🚀 gRPC server listening on :50051")
@@ -319,12 +524,30 @@ func startHTTPServer(srv *demoServer, mode string) {
 		log.Printf("Error occurred: %v", err)
 
 		// 使用默认错误处理器的逻辑
-		apiv1.DefaultErrorHandler(c, err)
+		runtime.DefaultErrorHandler(c, err)
+	}
+
+	// 审计中间件，对应 proto 中的 (protogin.method).middleware = "audit"，在处理函数返回后记录响应状态
+	auditMiddleware := func(next gin.HandlerFunc) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			next(c)
+			log.Printf("Audit: %s %s by %q -> %d", c.Request.Method, c.Request.URL.Path, c.GetHeader("X-Operator"), c.Writer.Status())
+		}
+	}
+
+	// 权限校验，对应 proto 中的 (protogin.method).auth_scopes
+	authorizer := func(c *gin.Context, scopes []string) error {
+		if c.GetHeader("X-Operator") == "" {
+			return status.Error(codes.Unauthenticated, "missing X-Operator header")
+		}
+		return nil
 	}
 
 	// 注册 HTTP 服务
 	apiv1.RegisterDemoServiceServerHTTPServer(srv, r,
-		apiv1.WithErrorHandler(customErrorHandler))
+		runtime.WithErrorHandler(customErrorHandler),
+		runtime.WithMiddleware("audit", auditMiddleware),
+		runtime.WithAuthorizer(authorizer))
 
 	log.Println("===============================================")
 	log.Println("🚀 Server starting on :8080")
@@ -337,9 +560,25 @@ func startHTTPServer(srv *demoServer, mode string) {
 	log.Println("   GET    /api/v1/users/:user_id")
 	log.Println("   POST   /api/v1/users")
 	log.Println("   PUT    /api/v1/users/:user_id")
+	log.Println("   PATCH  /api/v1/users/:user.user_id")
 	log.Println("   DELETE /api/v1/users/:user_id")
 	log.Println("   GET    /api/v1/users")
+	log.Println("   GET    /api/v1/legacy/users")
+	log.Println("   POST   /api/v1/users/:user_id:ban")
+	log.Println("   SEARCH /api/v1/users")
+	log.Println("   GET    /api/v1/users:search")
+	log.Println("   GET    /api/v1/orgs/:org.id/users/:user.user_id")
+	log.Println("   GET    /api/v1/projects/:name_1/books/:name_2")
+	log.Println("   GET    /api/v1/shelves/*name")
+	log.Println("   GET    /api/v1/authors/:author_id/posts/:status")
+	log.Println("   GET    /api/v1/users:watch (SSE / NDJSON)")
+	log.Println("   POST   /api/v1/users:import (NDJSON)")
+	log.Println("   POST   /api/v1/users/:user_id/avatar (multipart/form-data)")
+	log.Println("   PUT    /api/v1/users/:user_id/avatar (raw body)")
+	log.Println("   GET    /api/v1/users:export (CSV)")
+	log.Println("   GET    /api/v1/chat (WebSocket)")
 	log.Println("   POST   /api/v1/batch")
+	log.Println("   POST   /api/v1/batch/process")
 
 	if mode == "monolithic" {
 		log.Println("")
@@ -376,30 +615,3 @@ func startHTTPServer(srv *demoServer, mode string) {
 		log.Fatal(err)
 	}
 }
-
-func httpStatusToGRPCCode(status int) codes.Code {
-	switch status {
-	case 400:
-		return codes.InvalidArgument
-	case 401:
-		return codes.Unauthenticated
-	case 403:
-		return codes.PermissionDenied
-	case 404:
-		return codes.NotFound
-	case 409:
-		return codes.AlreadyExists
-	case 429:
-		return codes.ResourceExhausted
-	case 500:
-		return codes.Internal
-	case 501:
-		return codes.Unimplemented
-	case 503:
-		return codes.Unavailable
-	case 504:
-		return codes.DeadlineExceeded
-	default:
-		return codes.Unknown
-	}
-}
@@ -3,287 +3,634 @@
 package apiv1
 
 import (
-	json "encoding/json"
+	context "context"
+	runtime "github.com/JarrettGuo/protogin/runtime"
 	gin "github.com/gin-gonic/gin"
-	codes "google.golang.org/grpc/codes"
-	status "google.golang.org/grpc/status"
 	http "net/http"
+	time "time"
 )
 
-// BizError 业务错误接口（用于单体应用）
-type BizError interface {
-	error
-	GetCode() string
-	GetStatus() int
+// DemoServiceHTTPServer HTTP服务器结构
+type DemoServiceHTTPServer struct {
+	server DemoServiceServer
+	router gin.IRouter
+	opts   *runtime.ServerOptions
 }
 
-// ErrorHandler 定义错误处理器类型
-type ErrorHandler func(c *gin.Context, err error)
+// RegisterDemoServiceServerHTTPServer 注册HTTP服务器，错误处理器、请求体解码器等通过 runtime.ServerOption 配置
+func RegisterDemoServiceServerHTTPServer(srv DemoServiceServer, r gin.IRouter, opts ...runtime.ServerOption) {
+	s := DemoServiceHTTPServer{
+		server: srv,
+		router: r,
+		opts:   runtime.NewServerOptions(opts...),
+	}
 
-// HTTPServerOption 定义服务器选项
-type HTTPServerOption func(*DemoServiceHTTPServer)
+	s.RegisterService()
+}
 
-// DemoServiceHTTPServer HTTP服务器结构
-type DemoServiceHTTPServer struct {
-	server       DemoServiceServer
-	router       gin.IRouter
-	errorHandler ErrorHandler
+func (s *DemoServiceHTTPServer) GetUser_0(c *gin.Context) {
+	var in GetUserRequest
+
+	if err := runtime.PopulateQueryParameters(&in, c.Request.URL.Query(), "user_id"); err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	in.UserId = c.Param("user_id")
+
+	ctx := c.Request.Context()
+	out, err := s.server.GetUser(ctx, &in)
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	data, err := s.opts.Marshaler.Marshal(out)
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	s.opts.ResponseWriter.WriteResponse(c, http.StatusOK, data)
 }
 
-// DefaultErrorHandler 默认错误处理器（支持单体和分布式）
-func DefaultErrorHandler(c *gin.Context, err error) {
-	if err == nil {
+func (s *DemoServiceHTTPServer) CreateUser_0(c *gin.Context) {
+	var in CreateUserRequest
+
+	if err := s.opts.BodyDecoder.Decode(c.Request, &in); err != nil {
+		s.opts.ErrorHandler(c, err)
 		return
 	}
 
-	// 1. 优先检查是否是业务错误（单体应用场景）
-	if bizErr, ok := err.(BizError); ok {
-		c.JSON(bizErr.GetStatus(), gin.H{
-			"code":    bizErr.GetCode(),
-			"message": bizErr.Error(),
-			"success": false,
-		})
+	ctx := c.Request.Context()
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	out, err := s.server.CreateUser(ctx, &in)
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
 		return
 	}
 
-	// 2. 检查是否是 gRPC 错误（分布式场景）
-	if s, ok := status.FromError(err); ok {
-		httpStatus := grpcCodeToHTTP(s.Code())
-		c.JSON(httpStatus, gin.H{
-			"code":    s.Code().String(),
-			"message": s.Message(),
-			"success": false,
-		})
+	data, err := s.opts.Marshaler.Marshal(out)
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
 		return
 	}
 
-	// 3. 参数绑定错误
-	if _, ok := err.(*json.UnmarshalTypeError); ok {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code":    "INVALID_JSON",
-			"message": "请求格式错误",
-			"success": false,
-		})
+	s.opts.ResponseWriter.WriteResponse(c, 201, data)
+}
+
+func (s *DemoServiceHTTPServer) UpdateUser_0(c *gin.Context) {
+	var in UpdateUserRequest
+
+	if err := s.opts.BodyDecoder.Decode(c.Request, &in); err != nil {
+		s.opts.ErrorHandler(c, err)
 		return
 	}
 
-	// 4. 默认错误处理
-	c.JSON(http.StatusInternalServerError, gin.H{
-		"code":    "INTERNAL_ERROR",
-		"message": err.Error(),
-		"success": false,
-	})
+	in.UserId = c.Param("user_id")
+
+	ctx := c.Request.Context()
+	out, err := s.server.UpdateUser(ctx, &in)
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	data, err := s.opts.Marshaler.Marshal(out)
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	s.opts.ResponseWriter.WriteResponse(c, http.StatusOK, data)
 }
 
-// grpcCodeToHTTP 将 gRPC 状态码转换为 HTTP 状态码
-func grpcCodeToHTTP(code codes.Code) int {
-	switch code {
-	case codes.OK:
-		return http.StatusOK
-	case codes.InvalidArgument:
-		return http.StatusBadRequest
-	case codes.NotFound:
-		return http.StatusNotFound
-	case codes.AlreadyExists:
-		return http.StatusConflict
-	case codes.PermissionDenied:
-		return http.StatusForbidden
-	case codes.Unauthenticated:
-		return http.StatusUnauthorized
-	case codes.ResourceExhausted:
-		return http.StatusTooManyRequests
-	case codes.FailedPrecondition, codes.OutOfRange:
-		return http.StatusBadRequest
-	case codes.Unimplemented:
-		return http.StatusNotImplemented
-	case codes.Unavailable:
-		return http.StatusServiceUnavailable
-	case codes.DeadlineExceeded:
-		return http.StatusGatewayTimeout
-	case codes.Canceled:
-		return 499 // Client Closed Request
-	case codes.Unknown, codes.Internal, codes.DataLoss:
-		return http.StatusInternalServerError
-	case codes.Aborted:
-		return http.StatusConflict
-	default:
-		return http.StatusInternalServerError
+func (s *DemoServiceHTTPServer) PatchUser_0(c *gin.Context) {
+	var in PatchUserRequest
+
+	if err := runtime.PopulateQueryParameters(&in, c.Request.URL.Query(), "user.user_id", "user"); err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	if err := s.opts.BodyDecoder.DecodeField(c.Request, &in, "user"); err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	if in.User == nil {
+		in.User = &User{}
 	}
+	in.User.UserId = c.Param("user.user_id")
+
+	ctx := c.Request.Context()
+	out, err := s.server.PatchUser(ctx, &in)
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+	out = runtime.Redact(out)
+
+	data, err := s.opts.Marshaler.Marshal(out)
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	s.opts.ResponseWriter.WriteResponse(c, http.StatusOK, data)
 }
 
-// WithErrorHandler 设置自定义错误处理器
-func WithErrorHandler(h ErrorHandler) HTTPServerOption {
-	return func(s *DemoServiceHTTPServer) {
-		s.errorHandler = h
+func (s *DemoServiceHTTPServer) DeleteUser_0(c *gin.Context) {
+	var in DeleteUserRequest
+
+	if err := runtime.PopulateQueryParameters(&in, c.Request.URL.Query(), "user_id"); err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
 	}
+
+	in.UserId = c.Param("user_id")
+
+	ctx := c.Request.Context()
+	out, err := s.server.DeleteUser(ctx, &in)
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	data, err := s.opts.Marshaler.Marshal(out)
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	s.opts.ResponseWriter.WriteResponse(c, http.StatusOK, data)
 }
 
-// RegisterDemoServiceServerHTTPServer 注册HTTP服务器
-func RegisterDemoServiceServerHTTPServer(srv DemoServiceServer, r gin.IRouter, opts ...HTTPServerOption) {
-	s := DemoServiceHTTPServer{
-		server:       srv,
-		router:       r,
-		errorHandler: DefaultErrorHandler,
+func (s *DemoServiceHTTPServer) ListUsers_0(c *gin.Context) {
+	var in ListUsersRequest
+
+	if err := runtime.PopulateQueryParameters(&in, c.Request.URL.Query()); err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
 	}
 
-	// 应用选项
-	for _, opt := range opts {
-		opt(&s)
+	ctx := c.Request.Context()
+	out, err := s.server.ListUsers(ctx, &in)
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
 	}
+	out = runtime.Redact(out)
 
-	s.RegisterService()
+	data, err := s.opts.Marshaler.Marshal(out)
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "max-age=30")
+	s.opts.ResponseWriter.WriteResponse(c, http.StatusOK, data)
 }
 
-func (s *DemoServiceHTTPServer) GetUser_0(c *gin.Context) {
-	var in GetUserRequest
+func (s *DemoServiceHTTPServer) ListUsers_1(c *gin.Context) {
+	var in ListUsersRequest
+
+	if err := runtime.PopulateQueryParameters(&in, c.Request.URL.Query()); err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	out, err := s.server.ListUsers(ctx, &in)
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+	out = runtime.Redact(out)
+
+	data, err := s.opts.Marshaler.MarshalField(out, "users")
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "max-age=30")
+	s.opts.ResponseWriter.WriteResponse(c, http.StatusOK, data)
+}
+
+func (s *DemoServiceHTTPServer) BanUser_0(c *gin.Context) {
+	var in BanUserRequest
 
-	if err := c.ShouldBindQuery(&in); err != nil {
-		s.errorHandler(c, err)
+	if err := s.opts.BodyDecoder.Decode(c.Request, &in); err != nil {
+		s.opts.ErrorHandler(c, err)
 		return
 	}
 
+	runtime.ClearFields(&in, "operator")
+
+	if v, ok := runtime.Header(c, "X-Operator"); ok {
+		in.Operator = v
+	}
+
 	in.UserId = c.Param("user_id")
 
 	ctx := c.Request.Context()
-	out, err := s.server.GetUser(ctx, &in)
+	out, err := s.server.BanUser(ctx, &in)
 	if err != nil {
-		s.errorHandler(c, err)
+		s.opts.ErrorHandler(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"code":    "SUCCESS",
-		"message": "ok",
-		"data":    out,
-		"success": true,
-	})
+	data, err := s.opts.Marshaler.Marshal(out)
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	s.opts.ResponseWriter.WriteResponse(c, http.StatusOK, data)
 }
 
-func (s *DemoServiceHTTPServer) CreateUser_0(c *gin.Context) {
-	var in CreateUserRequest
+func (s *DemoServiceHTTPServer) SearchUsers_0(c *gin.Context) {
+	var in SearchUsersRequest
 
-	if err := c.ShouldBindJSON(&in); err != nil {
-		s.errorHandler(c, err)
+	if err := runtime.PopulateQueryParameters(&in, c.Request.URL.Query()); err != nil {
+		s.opts.ErrorHandler(c, err)
 		return
 	}
 
 	ctx := c.Request.Context()
-	out, err := s.server.CreateUser(ctx, &in)
+	out, err := s.server.SearchUsers(ctx, &in)
 	if err != nil {
-		s.errorHandler(c, err)
+		s.opts.ErrorHandler(c, err)
 		return
 	}
+	out = runtime.Redact(out)
 
-	c.JSON(http.StatusOK, gin.H{
-		"code":    "SUCCESS",
-		"message": "ok",
-		"data":    out,
-		"success": true,
-	})
+	data, err := s.opts.Marshaler.Marshal(out)
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	s.opts.ResponseWriter.WriteResponse(c, http.StatusOK, data)
 }
 
-func (s *DemoServiceHTTPServer) UpdateUser_0(c *gin.Context) {
-	var in UpdateUserRequest
+func (s *DemoServiceHTTPServer) SearchUsers_1(c *gin.Context) {
+	var in SearchUsersRequest
+
+	if err := runtime.PopulateQueryParameters(&in, c.Request.URL.Query()); err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	out, err := s.server.SearchUsers(ctx, &in)
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+	out = runtime.Redact(out)
+
+	data, err := s.opts.Marshaler.Marshal(out)
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	s.opts.ResponseWriter.WriteResponse(c, http.StatusOK, data)
+}
+
+func (s *DemoServiceHTTPServer) GetOrgUser_0(c *gin.Context) {
+	var in GetOrgUserRequest
 
-	if err := c.ShouldBindJSON(&in); err != nil {
-		s.errorHandler(c, err)
+	if err := runtime.PopulateQueryParameters(&in, c.Request.URL.Query(), "org.id", "user.user_id"); err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	if in.Org == nil {
+		in.Org = &Org{}
+	}
+	in.Org.Id = c.Param("org.id")
+
+	if in.User == nil {
+		in.User = &User{}
+	}
+	in.User.UserId = c.Param("user.user_id")
+
+	ctx := c.Request.Context()
+	out, err := s.server.GetOrgUser(ctx, &in)
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	data, err := s.opts.Marshaler.Marshal(out)
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	s.opts.ResponseWriter.WriteResponse(c, http.StatusOK, data)
+}
+
+var (
+	pattern_DemoService_GetBook_0_0 = runtime.MustPattern("projects/*/books/*")
+)
+
+func (s *DemoServiceHTTPServer) GetBook_0(c *gin.Context) {
+	var in GetBookRequest
+
+	if err := runtime.PopulateQueryParameters(&in, c.Request.URL.Query(), "name"); err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	p0, err := pattern_DemoService_GetBook_0_0.Build(c.Param("name_1"), c.Param("name_2"))
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	in.Name = p0
+
+	ctx := c.Request.Context()
+	out, err := s.server.GetBook(ctx, &in)
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	data, err := s.opts.Marshaler.Marshal(out)
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	s.opts.ResponseWriter.WriteResponse(c, http.StatusOK, data)
+}
+
+var (
+	pattern_DemoService_GetBook_1_0 = runtime.MustPattern("shelves/**")
+)
+
+func (s *DemoServiceHTTPServer) GetBook_1(c *gin.Context) {
+	var in GetBookRequest
+
+	if err := runtime.PopulateQueryParameters(&in, c.Request.URL.Query(), "name"); err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	p0, err := pattern_DemoService_GetBook_1_0.Build(c.Param("name"))
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	in.Name = p0
+
+	ctx := c.Request.Context()
+	out, err := s.server.GetBook(ctx, &in)
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	data, err := s.opts.Marshaler.Marshal(out)
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	s.opts.ResponseWriter.WriteResponse(c, http.StatusOK, data)
+}
+
+func (s *DemoServiceHTTPServer) ListPosts_0(c *gin.Context) {
+	var in ListPostsRequest
+
+	if err := runtime.PopulateQueryParameters(&in, c.Request.URL.Query(), "author_id", "status"); err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	p0, err := runtime.Int64(c.Param("author_id"))
+	if err != nil {
+		s.opts.ErrorHandler(c, runtime.NewBindError("path parameter", "author_id", err))
+		return
+	}
+
+	in.AuthorId = p0
+
+	p1, err := runtime.Enum(c.Param("status"), PostStatus_value)
+	if err != nil {
+		s.opts.ErrorHandler(c, runtime.NewBindError("path parameter", "status", err))
+		return
+	}
+
+	in.Status = PostStatus(p1)
+
+	ctx := c.Request.Context()
+	out, err := s.server.ListPosts(ctx, &in)
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	data, err := s.opts.Marshaler.Marshal(out)
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	s.opts.ResponseWriter.WriteResponse(c, http.StatusOK, data)
+}
+
+func (s *DemoServiceHTTPServer) WatchUsers_0(c *gin.Context) {
+	var in WatchUsersRequest
+
+	if err := runtime.PopulateQueryParameters(&in, c.Request.URL.Query()); err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	stream := runtime.NewServerStreamingServer[User](c, ctx, s.opts)
+	stream.Redact = true
+	stream.Finish(s.server.WatchUsers(&in, stream))
+}
+
+func (s *DemoServiceHTTPServer) ImportUsers_0(c *gin.Context) {
+	ctx := c.Request.Context()
+	stream := runtime.NewClientStreamingServer[CreateUserRequest, ImportUsersResponse](c, ctx, s.opts)
+	out, err := stream.Finish(s.server.ImportUsers(stream))
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	data, err := s.opts.Marshaler.Marshal(out)
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	s.opts.ResponseWriter.WriteResponse(c, http.StatusOK, data)
+}
+
+func (s *DemoServiceHTTPServer) UploadAvatar_0(c *gin.Context) {
+	runtime.LimitBody(c.Writer, c.Request, 10485760)
+	var in UploadAvatarRequest
+
+	if err := s.opts.BodyDecoder.Decode(c.Request, &in); err != nil {
+		s.opts.ErrorHandler(c, err)
 		return
 	}
 
 	in.UserId = c.Param("user_id")
 
 	ctx := c.Request.Context()
-	out, err := s.server.UpdateUser(ctx, &in)
+	out, err := s.server.UploadAvatar(ctx, &in)
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	data, err := s.opts.Marshaler.Marshal(out)
 	if err != nil {
-		s.errorHandler(c, err)
+		s.opts.ErrorHandler(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"code":    "SUCCESS",
-		"message": "ok",
-		"data":    out,
-		"success": true,
-	})
+	s.opts.ResponseWriter.WriteResponse(c, http.StatusOK, data)
 }
 
-func (s *DemoServiceHTTPServer) DeleteUser_0(c *gin.Context) {
-	var in DeleteUserRequest
+func (s *DemoServiceHTTPServer) ReplaceAvatar_0(c *gin.Context) {
+	var in ReplaceAvatarRequest
+
+	if err := runtime.PopulateQueryParameters(&in, c.Request.URL.Query(), "user_id", "content"); err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
 
-	if err := c.ShouldBindQuery(&in); err != nil {
-		s.errorHandler(c, err)
+	if err := runtime.DecodeHTTPBody(c.Request, &in, "content"); err != nil {
+		s.opts.ErrorHandler(c, err)
 		return
 	}
 
 	in.UserId = c.Param("user_id")
 
 	ctx := c.Request.Context()
-	out, err := s.server.DeleteUser(ctx, &in)
+	out, err := s.server.ReplaceAvatar(ctx, &in)
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	data, err := s.opts.Marshaler.Marshal(out)
 	if err != nil {
-		s.errorHandler(c, err)
+		s.opts.ErrorHandler(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"code":    "SUCCESS",
-		"message": "ok",
-		"data":    out,
-		"success": true,
-	})
+	s.opts.ResponseWriter.WriteResponse(c, http.StatusOK, data)
 }
 
-func (s *DemoServiceHTTPServer) ListUsers_0(c *gin.Context) {
-	var in ListUsersRequest
+func (s *DemoServiceHTTPServer) ExportUsers_0(c *gin.Context) {
+	var in ExportUsersRequest
 
-	if err := c.ShouldBindQuery(&in); err != nil {
-		s.errorHandler(c, err)
+	if err := runtime.PopulateQueryParameters(&in, c.Request.URL.Query()); err != nil {
+		s.opts.ErrorHandler(c, err)
 		return
 	}
 
 	ctx := c.Request.Context()
-	out, err := s.server.ListUsers(ctx, &in)
+	out, err := s.server.ExportUsers(ctx, &in)
 	if err != nil {
-		s.errorHandler(c, err)
+		s.opts.ErrorHandler(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"code":    "SUCCESS",
-		"message": "ok",
-		"data":    out,
-		"success": true,
-	})
+	runtime.WriteHTTPBody(c, http.StatusOK, out)
+}
+
+func (s *DemoServiceHTTPServer) Chat_0(c *gin.Context) {
+	ctx := c.Request.Context()
+	stream := runtime.NewBidiStreamingServer[ChatMessage, ChatMessage](c, ctx, s.opts)
+	stream.Finish(s.server.Chat(stream))
 }
 
 func (s *DemoServiceHTTPServer) BatchOperation_0(c *gin.Context) {
 	var in BatchRequest
 
-	if err := c.ShouldBindJSON(&in); err != nil {
-		s.errorHandler(c, err)
+	if err := s.opts.BodyDecoder.Decode(c.Request, &in); err != nil {
+		s.opts.ErrorHandler(c, err)
 		return
 	}
 
 	ctx := c.Request.Context()
 	out, err := s.server.BatchOperation(ctx, &in)
 	if err != nil {
-		s.errorHandler(c, err)
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	data, err := s.opts.Marshaler.Marshal(out)
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	s.opts.ResponseWriter.WriteResponse(c, http.StatusOK, data)
+}
+
+func (s *DemoServiceHTTPServer) BatchOperation_1(c *gin.Context) {
+	var in BatchRequest
+
+	if err := s.opts.BodyDecoder.Decode(c.Request, &in); err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	out, err := s.server.BatchOperation(ctx, &in)
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+
+	data, err := s.opts.Marshaler.Marshal(out)
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"code":    "SUCCESS",
-		"message": "ok",
-		"data":    out,
-		"success": true,
-	})
+	s.opts.ResponseWriter.WriteResponse(c, http.StatusOK, data)
 }
 
 func (s *DemoServiceHTTPServer) RegisterService() {
-	s.router.Handle("GET", "/api/v1/users/:user_id", s.GetUser_0)
-	s.router.Handle("POST", "/api/v1/users", s.CreateUser_0)
-	s.router.Handle("PUT", "/api/v1/users/:user_id", s.UpdateUser_0)
-	s.router.Handle("DELETE", "/api/v1/users/:user_id", s.DeleteUser_0)
-	s.router.Handle("GET", "/api/v1/users", s.ListUsers_0)
-	s.router.Handle("POST", "/api/v1/batch", s.BatchOperation_0)
+	s.opts.Routes.Handle(s.router, "GET", "/api/v1/users/:user_id", "", s.GetUser_0, s.opts.ErrorHandler)
+	s.opts.Routes.Handle(s.router, "POST", "/api/v1/users", "", s.CreateUser_0, s.opts.ErrorHandler)
+	s.opts.Routes.Handle(s.router, "PUT", "/api/v1/users/:user_id", "", s.UpdateUser_0, s.opts.ErrorHandler)
+	s.opts.Routes.Handle(s.router, "PATCH", "/api/v1/users/:user.user_id", "", s.PatchUser_0, s.opts.ErrorHandler)
+	s.opts.Routes.Handle(s.router, "DELETE", "/api/v1/users/:user_id", "", s.DeleteUser_0, s.opts.ErrorHandler)
+	s.opts.Routes.Handle(s.router, "GET", "/api/v1/users", "", s.ListUsers_0, s.opts.ErrorHandler)
+	s.opts.Routes.Handle(s.router, "GET", "/api/v1/legacy/users", "", s.ListUsers_1, s.opts.ErrorHandler)
+	s.opts.Routes.Handle(s.router, "POST", "/api/v1/users/:user_id", "ban", s.opts.Wrap(s.BanUser_0, runtime.RouteOptions{Middleware: []string{"audit"}, AuthScopes: []string{"user.admin"}}), s.opts.ErrorHandler)
+	s.opts.Routes.Handle(s.router, "SEARCH", "/api/v1/users", "", s.SearchUsers_0, s.opts.ErrorHandler)
+	s.opts.Routes.Handle(s.router, "GET", "/api/v1/users", "search", s.SearchUsers_1, s.opts.ErrorHandler)
+	s.opts.Routes.Handle(s.router, "GET", "/api/v1/orgs/:org.id/users/:user.user_id", "", s.GetOrgUser_0, s.opts.ErrorHandler)
+	s.opts.Routes.Handle(s.router, "GET", "/api/v1/projects/:name_1/books/:name_2", "", s.GetBook_0, s.opts.ErrorHandler)
+	s.opts.Routes.Handle(s.router, "GET", "/api/v1/shelves/*name", "", s.GetBook_1, s.opts.ErrorHandler)
+	s.opts.Routes.Handle(s.router, "GET", "/api/v1/authors/:author_id/posts/:status", "", s.ListPosts_0, s.opts.ErrorHandler)
+	s.opts.Routes.Handle(s.router, "GET", "/api/v1/users", "watch", s.WatchUsers_0, s.opts.ErrorHandler)
+	s.opts.Routes.Handle(s.router, "POST", "/api/v1/users", "import", s.ImportUsers_0, s.opts.ErrorHandler)
+	s.opts.Routes.Handle(s.router, "POST", "/api/v1/users/:user_id/avatar", "", s.UploadAvatar_0, s.opts.ErrorHandler)
+	s.opts.Routes.Handle(s.router, "PUT", "/api/v1/users/:user_id/avatar", "", s.ReplaceAvatar_0, s.opts.ErrorHandler)
+	s.opts.Routes.Handle(s.router, "GET", "/api/v1/users", "export", s.ExportUsers_0, s.opts.ErrorHandler)
+	s.opts.Routes.Handle(s.router, "GET", "/api/v1/chat", "", s.Chat_0, s.opts.ErrorHandler)
+	s.opts.Routes.Handle(s.router, "POST", "/api/v1/batch", "", s.BatchOperation_0, s.opts.ErrorHandler)
+	s.opts.Routes.Handle(s.router, "POST", "/api/v1/batch/process", "", s.BatchOperation_1, s.opts.ErrorHandler)
 
 }
@@ -8,6 +8,7 @@ package apiv1
 
 import (
 	context "context"
+	httpbody "google.golang.org/genproto/googleapis/api/httpbody"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
@@ -22,8 +23,20 @@ const (
 	DemoService_GetUser_FullMethodName        = "/api.v1.DemoService/GetUser"
 	DemoService_CreateUser_FullMethodName     = "/api.v1.DemoService/CreateUser"
 	DemoService_UpdateUser_FullMethodName     = "/api.v1.DemoService/UpdateUser"
+	DemoService_PatchUser_FullMethodName      = "/api.v1.DemoService/PatchUser"
 	DemoService_DeleteUser_FullMethodName     = "/api.v1.DemoService/DeleteUser"
 	DemoService_ListUsers_FullMethodName      = "/api.v1.DemoService/ListUsers"
+	DemoService_BanUser_FullMethodName        = "/api.v1.DemoService/BanUser"
+	DemoService_SearchUsers_FullMethodName    = "/api.v1.DemoService/SearchUsers"
+	DemoService_GetOrgUser_FullMethodName     = "/api.v1.DemoService/GetOrgUser"
+	DemoService_GetBook_FullMethodName        = "/api.v1.DemoService/GetBook"
+	DemoService_ListPosts_FullMethodName      = "/api.v1.DemoService/ListPosts"
+	DemoService_WatchUsers_FullMethodName     = "/api.v1.DemoService/WatchUsers"
+	DemoService_ImportUsers_FullMethodName    = "/api.v1.DemoService/ImportUsers"
+	DemoService_UploadAvatar_FullMethodName   = "/api.v1.DemoService/UploadAvatar"
+	DemoService_ReplaceAvatar_FullMethodName  = "/api.v1.DemoService/ReplaceAvatar"
+	DemoService_ExportUsers_FullMethodName    = "/api.v1.DemoService/ExportUsers"
+	DemoService_Chat_FullMethodName           = "/api.v1.DemoService/Chat"
 	DemoService_BatchOperation_FullMethodName = "/api.v1.DemoService/BatchOperation"
 )
 
@@ -39,10 +52,34 @@ type DemoServiceClient interface {
 	CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*CreateUserResponse, error)
 	// 更新用户信息
 	UpdateUser(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*UpdateUserResponse, error)
+	// 部分更新用户（请求体只映射到 user 字段，validate_only 来自 query）
+	PatchUser(ctx context.Context, in *PatchUserRequest, opts ...grpc.CallOption) (*User, error)
 	// 删除用户
 	DeleteUser(ctx context.Context, in *DeleteUserRequest, opts ...grpc.CallOption) (*DeleteUserResponse, error)
 	// 获取用户列表
 	ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error)
+	// 封禁用户（自定义动词）
+	BanUser(ctx context.Context, in *BanUserRequest, opts ...grpc.CallOption) (*BanUserResponse, error)
+	// 搜索用户（自定义 HTTP 方法和自定义动词）
+	SearchUsers(ctx context.Context, in *SearchUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error)
+	// 获取组织下的用户（嵌套字段路径参数）
+	GetOrgUser(ctx context.Context, in *GetOrgUserRequest, opts ...grpc.CallOption) (*GetUserResponse, error)
+	// 获取图书（通配路径模板，name 为完整的资源名）
+	GetBook(ctx context.Context, in *GetBookRequest, opts ...grpc.CallOption) (*Book, error)
+	// 获取作者的文章列表（非字符串类型的路径参数）
+	ListPosts(ctx context.Context, in *ListPostsRequest, opts ...grpc.CallOption) (*ListPostsResponse, error)
+	// 订阅用户变更（服务端流式，按 Accept 返回 SSE 或 NDJSON）
+	WatchUsers(ctx context.Context, in *WatchUsersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[User], error)
+	// 批量导入用户（客户端流式，请求体为 NDJSON 或长度前缀的 protojson）
+	ImportUsers(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[CreateUserRequest, ImportUsersResponse], error)
+	// 上传头像（multipart/form-data，文件写入 protogin.File 字段）
+	UploadAvatar(ctx context.Context, in *UploadAvatarRequest, opts ...grpc.CallOption) (*UploadAvatarResponse, error)
+	// 以原始请求体替换头像（body 字段为 google.api.HttpBody）
+	ReplaceAvatar(ctx context.Context, in *ReplaceAvatarRequest, opts ...grpc.CallOption) (*UploadAvatarResponse, error)
+	// 导出用户（google.api.HttpBody 响应，返回 CSV 文件，支持 Range）
+	ExportUsers(ctx context.Context, in *ExportUsersRequest, opts ...grpc.CallOption) (*httpbody.HttpBody, error)
+	// 聊天（双向流式，通过 WebSocket 连接）
+	Chat(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ChatMessage, ChatMessage], error)
 	// 批量操作示例（多个 HTTP 绑定）
 	BatchOperation(ctx context.Context, in *BatchRequest, opts ...grpc.CallOption) (*BatchResponse, error)
 }
@@ -85,6 +122,16 @@ func (c *demoServiceClient) UpdateUser(ctx context.Context, in *UpdateUserReques
 	return out, nil
 }
 
+func (c *demoServiceClient) PatchUser(ctx context.Context, in *PatchUserRequest, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, DemoService_PatchUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *demoServiceClient) DeleteUser(ctx context.Context, in *DeleteUserRequest, opts ...grpc.CallOption) (*DeleteUserResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(DeleteUserResponse)
@@ -105,6 +152,131 @@ func (c *demoServiceClient) ListUsers(ctx context.Context, in *ListUsersRequest,
 	return out, nil
 }
 
+func (c *demoServiceClient) BanUser(ctx context.Context, in *BanUserRequest, opts ...grpc.CallOption) (*BanUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BanUserResponse)
+	err := c.cc.Invoke(ctx, DemoService_BanUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *demoServiceClient) SearchUsers(ctx context.Context, in *SearchUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListUsersResponse)
+	err := c.cc.Invoke(ctx, DemoService_SearchUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *demoServiceClient) GetOrgUser(ctx context.Context, in *GetOrgUserRequest, opts ...grpc.CallOption) (*GetUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUserResponse)
+	err := c.cc.Invoke(ctx, DemoService_GetOrgUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *demoServiceClient) GetBook(ctx context.Context, in *GetBookRequest, opts ...grpc.CallOption) (*Book, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Book)
+	err := c.cc.Invoke(ctx, DemoService_GetBook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *demoServiceClient) ListPosts(ctx context.Context, in *ListPostsRequest, opts ...grpc.CallOption) (*ListPostsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPostsResponse)
+	err := c.cc.Invoke(ctx, DemoService_ListPosts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *demoServiceClient) WatchUsers(ctx context.Context, in *WatchUsersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[User], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DemoService_ServiceDesc.Streams[0], DemoService_WatchUsers_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchUsersRequest, User]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DemoService_WatchUsersClient = grpc.ServerStreamingClient[User]
+
+func (c *demoServiceClient) ImportUsers(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[CreateUserRequest, ImportUsersResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DemoService_ServiceDesc.Streams[1], DemoService_ImportUsers_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[CreateUserRequest, ImportUsersResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DemoService_ImportUsersClient = grpc.ClientStreamingClient[CreateUserRequest, ImportUsersResponse]
+
+func (c *demoServiceClient) UploadAvatar(ctx context.Context, in *UploadAvatarRequest, opts ...grpc.CallOption) (*UploadAvatarResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UploadAvatarResponse)
+	err := c.cc.Invoke(ctx, DemoService_UploadAvatar_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *demoServiceClient) ReplaceAvatar(ctx context.Context, in *ReplaceAvatarRequest, opts ...grpc.CallOption) (*UploadAvatarResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UploadAvatarResponse)
+	err := c.cc.Invoke(ctx, DemoService_ReplaceAvatar_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *demoServiceClient) ExportUsers(ctx context.Context, in *ExportUsersRequest, opts ...grpc.CallOption) (*httpbody.HttpBody, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(httpbody.HttpBody)
+	err := c.cc.Invoke(ctx, DemoService_ExportUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *demoServiceClient) Chat(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ChatMessage, ChatMessage], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DemoService_ServiceDesc.Streams[2], DemoService_Chat_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ChatMessage, ChatMessage]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DemoService_ChatClient = grpc.BidiStreamingClient[ChatMessage, ChatMessage]
+
 func (c *demoServiceClient) BatchOperation(ctx context.Context, in *BatchRequest, opts ...grpc.CallOption) (*BatchResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(BatchResponse)
@@ -127,10 +299,34 @@ type DemoServiceServer interface {
 	CreateUser(context.Context, *CreateUserRequest) (*CreateUserResponse, error)
 	// 更新用户信息
 	UpdateUser(context.Context, *UpdateUserRequest) (*UpdateUserResponse, error)
+	// 部分更新用户（请求体只映射到 user 字段，validate_only 来自 query）
+	PatchUser(context.Context, *PatchUserRequest) (*User, error)
 	// 删除用户
 	DeleteUser(context.Context, *DeleteUserRequest) (*DeleteUserResponse, error)
 	// 获取用户列表
 	ListUsers(context.Context, *ListUsersRequest) (*ListUsersResponse, error)
+	// 封禁用户（自定义动词）
+	BanUser(context.Context, *BanUserRequest) (*BanUserResponse, error)
+	// 搜索用户（自定义 HTTP 方法和自定义动词）
+	SearchUsers(context.Context, *SearchUsersRequest) (*ListUsersResponse, error)
+	// 获取组织下的用户（嵌套字段路径参数）
+	GetOrgUser(context.Context, *GetOrgUserRequest) (*GetUserResponse, error)
+	// 获取图书（通配路径模板，name 为完整的资源名）
+	GetBook(context.Context, *GetBookRequest) (*Book, error)
+	// 获取作者的文章列表（非字符串类型的路径参数）
+	ListPosts(context.Context, *ListPostsRequest) (*ListPostsResponse, error)
+	// 订阅用户变更（服务端流式，按 Accept 返回 SSE 或 NDJSON）
+	WatchUsers(*WatchUsersRequest, grpc.ServerStreamingServer[User]) error
+	// 批量导入用户（客户端流式，请求体为 NDJSON 或长度前缀的 protojson）
+	ImportUsers(grpc.ClientStreamingServer[CreateUserRequest, ImportUsersResponse]) error
+	// 上传头像（multipart/form-data，文件写入 protogin.File 字段）
+	UploadAvatar(context.Context, *UploadAvatarRequest) (*UploadAvatarResponse, error)
+	// 以原始请求体替换头像（body 字段为 google.api.HttpBody）
+	ReplaceAvatar(context.Context, *ReplaceAvatarRequest) (*UploadAvatarResponse, error)
+	// 导出用户（google.api.HttpBody 响应，返回 CSV 文件，支持 Range）
+	ExportUsers(context.Context, *ExportUsersRequest) (*httpbody.HttpBody, error)
+	// 聊天（双向流式，通过 WebSocket 连接）
+	Chat(grpc.BidiStreamingServer[ChatMessage, ChatMessage]) error
 	// 批量操作示例（多个 HTTP 绑定）
 	BatchOperation(context.Context, *BatchRequest) (*BatchResponse, error)
 	mustEmbedUnimplementedDemoServiceServer()
@@ -152,12 +348,48 @@ func (UnimplementedDemoServiceServer) CreateUser(context.Context, *CreateUserReq
 func (UnimplementedDemoServiceServer) UpdateUser(context.Context, *UpdateUserRequest) (*UpdateUserResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateUser not implemented")
 }
+func (UnimplementedDemoServiceServer) PatchUser(context.Context, *PatchUserRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PatchUser not implemented")
+}
 func (UnimplementedDemoServiceServer) DeleteUser(context.Context, *DeleteUserRequest) (*DeleteUserResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteUser not implemented")
 }
 func (UnimplementedDemoServiceServer) ListUsers(context.Context, *ListUsersRequest) (*ListUsersResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListUsers not implemented")
 }
+func (UnimplementedDemoServiceServer) BanUser(context.Context, *BanUserRequest) (*BanUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BanUser not implemented")
+}
+func (UnimplementedDemoServiceServer) SearchUsers(context.Context, *SearchUsersRequest) (*ListUsersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchUsers not implemented")
+}
+func (UnimplementedDemoServiceServer) GetOrgUser(context.Context, *GetOrgUserRequest) (*GetUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrgUser not implemented")
+}
+func (UnimplementedDemoServiceServer) GetBook(context.Context, *GetBookRequest) (*Book, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBook not implemented")
+}
+func (UnimplementedDemoServiceServer) ListPosts(context.Context, *ListPostsRequest) (*ListPostsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPosts not implemented")
+}
+func (UnimplementedDemoServiceServer) WatchUsers(*WatchUsersRequest, grpc.ServerStreamingServer[User]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchUsers not implemented")
+}
+func (UnimplementedDemoServiceServer) ImportUsers(grpc.ClientStreamingServer[CreateUserRequest, ImportUsersResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ImportUsers not implemented")
+}
+func (UnimplementedDemoServiceServer) UploadAvatar(context.Context, *UploadAvatarRequest) (*UploadAvatarResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UploadAvatar not implemented")
+}
+func (UnimplementedDemoServiceServer) ReplaceAvatar(context.Context, *ReplaceAvatarRequest) (*UploadAvatarResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplaceAvatar not implemented")
+}
+func (UnimplementedDemoServiceServer) ExportUsers(context.Context, *ExportUsersRequest) (*httpbody.HttpBody, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportUsers not implemented")
+}
+func (UnimplementedDemoServiceServer) Chat(grpc.BidiStreamingServer[ChatMessage, ChatMessage]) error {
+	return status.Errorf(codes.Unimplemented, "method Chat not implemented")
+}
 func (UnimplementedDemoServiceServer) BatchOperation(context.Context, *BatchRequest) (*BatchResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BatchOperation not implemented")
 }
@@ -236,6 +468,24 @@ func _DemoService_UpdateUser_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DemoService_PatchUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PatchUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DemoServiceServer).PatchUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DemoService_PatchUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DemoServiceServer).PatchUser(ctx, req.(*PatchUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _DemoService_DeleteUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DeleteUserRequest)
 	if err := dec(in); err != nil {
@@ -272,6 +522,175 @@ func _DemoService_ListUsers_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DemoService_BanUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BanUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DemoServiceServer).BanUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DemoService_BanUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DemoServiceServer).BanUser(ctx, req.(*BanUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DemoService_SearchUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DemoServiceServer).SearchUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DemoService_SearchUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DemoServiceServer).SearchUsers(ctx, req.(*SearchUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DemoService_GetOrgUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrgUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DemoServiceServer).GetOrgUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DemoService_GetOrgUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DemoServiceServer).GetOrgUser(ctx, req.(*GetOrgUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DemoService_GetBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DemoServiceServer).GetBook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DemoService_GetBook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DemoServiceServer).GetBook(ctx, req.(*GetBookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DemoService_ListPosts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPostsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DemoServiceServer).ListPosts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DemoService_ListPosts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DemoServiceServer).ListPosts(ctx, req.(*ListPostsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DemoService_WatchUsers_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchUsersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DemoServiceServer).WatchUsers(m, &grpc.GenericServerStream[WatchUsersRequest, User]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DemoService_WatchUsersServer = grpc.ServerStreamingServer[User]
+
+func _DemoService_ImportUsers_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DemoServiceServer).ImportUsers(&grpc.GenericServerStream[CreateUserRequest, ImportUsersResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DemoService_ImportUsersServer = grpc.ClientStreamingServer[CreateUserRequest, ImportUsersResponse]
+
+func _DemoService_UploadAvatar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UploadAvatarRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DemoServiceServer).UploadAvatar(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DemoService_UploadAvatar_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DemoServiceServer).UploadAvatar(ctx, req.(*UploadAvatarRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DemoService_ReplaceAvatar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplaceAvatarRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DemoServiceServer).ReplaceAvatar(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DemoService_ReplaceAvatar_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DemoServiceServer).ReplaceAvatar(ctx, req.(*ReplaceAvatarRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DemoService_ExportUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DemoServiceServer).ExportUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DemoService_ExportUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DemoServiceServer).ExportUsers(ctx, req.(*ExportUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DemoService_Chat_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DemoServiceServer).Chat(&grpc.GenericServerStream[ChatMessage, ChatMessage]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DemoService_ChatServer = grpc.BidiStreamingServer[ChatMessage, ChatMessage]
+
 func _DemoService_BatchOperation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(BatchRequest)
 	if err := dec(in); err != nil {
@@ -309,6 +728,10 @@ var DemoService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateUser",
 			Handler:    _DemoService_UpdateUser_Handler,
 		},
+		{
+			MethodName: "PatchUser",
+			Handler:    _DemoService_PatchUser_Handler,
+		},
 		{
 			MethodName: "DeleteUser",
 			Handler:    _DemoService_DeleteUser_Handler,
@@ -317,11 +740,60 @@ var DemoService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListUsers",
 			Handler:    _DemoService_ListUsers_Handler,
 		},
+		{
+			MethodName: "BanUser",
+			Handler:    _DemoService_BanUser_Handler,
+		},
+		{
+			MethodName: "SearchUsers",
+			Handler:    _DemoService_SearchUsers_Handler,
+		},
+		{
+			MethodName: "GetOrgUser",
+			Handler:    _DemoService_GetOrgUser_Handler,
+		},
+		{
+			MethodName: "GetBook",
+			Handler:    _DemoService_GetBook_Handler,
+		},
+		{
+			MethodName: "ListPosts",
+			Handler:    _DemoService_ListPosts_Handler,
+		},
+		{
+			MethodName: "UploadAvatar",
+			Handler:    _DemoService_UploadAvatar_Handler,
+		},
+		{
+			MethodName: "ReplaceAvatar",
+			Handler:    _DemoService_ReplaceAvatar_Handler,
+		},
+		{
+			MethodName: "ExportUsers",
+			Handler:    _DemoService_ExportUsers_Handler,
+		},
 		{
 			MethodName: "BatchOperation",
 			Handler:    _DemoService_BatchOperation_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchUsers",
+			Handler:       _DemoService_WatchUsers_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ImportUsers",
+			Handler:       _DemoService_ImportUsers_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Chat",
+			Handler:       _DemoService_Chat_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
 	Metadata: "api/v1/api.proto",
 }
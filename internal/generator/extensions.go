@@ -0,0 +1,240 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/JarrettGuo/protogin/gen/protogin"
+)
+
+// methodOptions 返回方法的 (protogin.method) 选项，没有设置时返回 nil，Get 方法可以直接调用
+func methodOptions(m *protogen.Method) *protogin.MethodOptions {
+	opts, _ := proto.GetExtension(m.Desc.Options(), protogin.E_Method).(*protogin.MethodOptions)
+	return opts
+}
+
+// serviceOptions 返回服务的 (protogin.service) 选项
+func serviceOptions(s *protogen.Service) *protogin.ServiceOptions {
+	opts, _ := proto.GetExtension(s.Desc.Options(), protogin.E_Service).(*protogin.ServiceOptions)
+	return opts
+}
+
+// fieldOptions 返回字段的 (protogin.field) 选项
+func fieldOptions(f *protogen.Field) *protogin.FieldOptions {
+	opts, _ := proto.GetExtension(f.Desc.Options(), protogin.E_Field).(*protogin.FieldOptions)
+	return opts
+}
+
+// applyMethodOptions 把 (protogin.method) 和 (protogin.service) 选项应用到 http 绑定
+func applyMethodOptions(g *protogen.GeneratedFile, m *protogen.Method, md *method, opts *Options) error {
+	mo := methodOptions(m)
+
+	if status := mo.GetSuccessStatus(); status != 0 {
+		if status < 200 || status > 299 {
+			return fmt.Errorf("(protogin.method).success_status %d is not a 2xx status", status)
+		}
+		md.SuccessStatus = int(status)
+	}
+
+	if mo.GetTimeout() != nil {
+		d, err := duration(mo.GetTimeout())
+		if err != nil {
+			return fmt.Errorf("(protogin.method).timeout: %v", err)
+		}
+		md.Timeout = durationExpr(g, d)
+		md.WithTimeout = g.QualifiedGoIdent(contextPkg.Ident("WithTimeout"))
+	}
+
+	if mo.GetCacheTtl() != nil {
+		d, err := duration(mo.GetCacheTtl())
+		if err != nil {
+			return fmt.Errorf("(protogin.method).cache_ttl: %v", err)
+		}
+		if d%time.Second != 0 {
+			return fmt.Errorf("(protogin.method).cache_ttl %v must be whole seconds", d)
+		}
+		md.CacheControl = "max-age=" + strconv.FormatInt(int64(d/time.Second), 10)
+	}
+
+	if size := mo.GetMaxBodySize(); size != 0 {
+		if size < 0 {
+			return fmt.Errorf("(protogin.method).max_body_size %d is negative", size)
+		}
+		if md.Body == "" {
+			return fmt.Errorf("(protogin.method).max_body_size requires a body")
+		}
+		md.MaxBodySize = size
+	}
+
+	// 不设置 envelope 时使用注册时配置的 ResponseWriter；envelope=false 的插件参数下显式开启信封时使用默认信封
+	md.Writer = "s.opts.ResponseWriter"
+	if mo != nil && mo.Envelope != nil {
+		switch {
+		case !mo.GetEnvelope():
+			md.Writer = g.QualifiedGoIdent(opts.runtimePkg().Ident("RawWriter")) + "{}"
+		case !opts.Envelope:
+			md.Writer = g.QualifiedGoIdent(opts.runtimePkg().Ident("EnvelopeWriter")) + "{}"
+		}
+	}
+
+	var route []string
+	middleware := appendUnique(serviceOptions(m.Parent).GetMiddleware(), mo.GetMiddleware()...)
+	if len(middleware) > 0 {
+		route = append(route, "Middleware: "+stringSlice(middleware))
+	}
+	if len(mo.GetAuthScopes()) > 0 {
+		route = append(route, "AuthScopes: "+stringSlice(mo.GetAuthScopes()))
+	}
+	for _, v := range append(middleware, mo.GetAuthScopes()...) {
+		if v == "" {
+			return fmt.Errorf("empty middleware tag or auth scope")
+		}
+	}
+	if len(route) > 0 {
+		md.RouteOptions = g.QualifiedGoIdent(opts.runtimePkg().Ident("RouteOptions")) + "{" + strings.Join(route, ", ") + "}"
+	}
+
+	if hasRedactedFields(m.Output, make(map[*protogen.Message]bool)) {
+		md.Redact = g.QualifiedGoIdent(opts.runtimePkg().Ident("Redact"))
+	}
+	return nil
+}
+
+// resolveMetaParams 解析请求消息顶层字段上的 (protogin.field).header 和 cookie 绑定
+func resolveMetaParams(g *protogen.GeneratedFile, m *protogen.Method, md *method, runtimePkg protogen.GoImportPath) error {
+	for _, f := range m.Input.Fields {
+		fo := fieldOptions(f)
+		if fo.GetHeader() == "" && fo.GetCookie() == "" {
+			continue
+		}
+
+		name := string(f.Desc.Name())
+		if md.ClientStreaming {
+			return fmt.Errorf("field %q: header and cookie bindings are not supported for %s methods", name, streamingKind(m))
+		}
+		if fo.GetHeader() != "" && fo.GetCookie() != "" {
+			return fmt.Errorf("field %q cannot be bound to both header and cookie", name)
+		}
+		if name == md.Body {
+			return fmt.Errorf("field %q cannot be bound to both body and header or cookie", name)
+		}
+		for _, p := range md.PathParams {
+			if p.Name == name {
+				return fmt.Errorf("field %q cannot be bound to both path and header or cookie", name)
+			}
+		}
+
+		mp := &metaParam{pathParam: &pathParam{Name: name}}
+		if fo.GetHeader() != "" {
+			mp.Source, mp.Key = "header", fo.GetHeader()
+			mp.Lookup = g.QualifiedGoIdent(runtimePkg.Ident("Header"))
+		} else {
+			mp.Source, mp.Key = "cookie", fo.GetCookie()
+			mp.Lookup = g.QualifiedGoIdent(runtimePkg.Ident("Cookie"))
+		}
+		if !isToken(mp.Key) {
+			return fmt.Errorf("field %q: %s name %q is not a valid token", name, mp.Source, mp.Key)
+		}
+		if err := resolvePathParam(g, m.Input, mp.pathParam, runtimePkg); err != nil {
+			return fmt.Errorf("%s %q: %v", mp.Source, mp.Key, err)
+		}
+		md.MetaParams = append(md.MetaParams, mp)
+	}
+	return nil
+}
+
+// isToken s 是否为 RFC 7230 的 token，header 和 cookie 的名称都必须是 token
+func isToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case strings.ContainsRune("!#$%&'*+-.^_`|~", r):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// hasRedactedFields 消息（包括嵌套消息）中是否有 (protogin.field).redact 字段
+func hasRedactedFields(msg *protogen.Message, seen map[*protogen.Message]bool) bool {
+	if seen[msg] {
+		return false
+	}
+	seen[msg] = true
+
+	for _, f := range msg.Fields {
+		if fieldOptions(f).GetRedact() {
+			return true
+		}
+		if f.Message != nil && hasRedactedFields(f.Message, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+func duration(d *durationpb.Duration) (time.Duration, error) {
+	if err := d.CheckValid(); err != nil {
+		return 0, err
+	}
+	if d.AsDuration() <= 0 {
+		return 0, fmt.Errorf("must be positive")
+	}
+	return d.AsDuration(), nil
+}
+
+// durationExpr 时长的 Go 表达式，如 5 * time.Second
+func durationExpr(g *protogen.GeneratedFile, d time.Duration) string {
+	units := []struct {
+		unit time.Duration
+		name string
+	}{
+		{time.Hour, "Hour"},
+		{time.Minute, "Minute"},
+		{time.Second, "Second"},
+		{time.Millisecond, "Millisecond"},
+	}
+	for _, u := range units {
+		if d%u.unit == 0 {
+			return fmt.Sprintf("%d * %s", d/u.unit, g.QualifiedGoIdent(timePkg.Ident(u.name)))
+		}
+	}
+	return fmt.Sprintf("%s(%d)", g.QualifiedGoIdent(timePkg.Ident("Duration")), int64(d))
+}
+
+// appendUnique 追加不重复的元素，保持原有顺序
+func appendUnique(list []string, items ...string) []string {
+	out := append([]string(nil), list...)
+	for _, item := range items {
+		dup := false
+		for _, v := range out {
+			if v == item {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// stringSlice []string 字面量
+func stringSlice(list []string) string {
+	quoted := make([]string, len(list))
+	for i, v := range list {
+		quoted[i] = strconv.Quote(v)
+	}
+	return "[]string{" + strings.Join(quoted, ", ") + "}"
+}
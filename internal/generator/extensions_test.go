@@ -0,0 +1,141 @@
+package generator
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/compiler/protogen"
+
+	"github.com/JarrettGuo/protogin/internal/testproto"
+)
+
+// metaProto 带有 protogin 选项的测试文件，fields 为请求消息的字段，services 为 service 定义
+const metaProto = `
+name: "test/v1/meta.proto"
+package: "test.v1"
+syntax: "proto3"
+dependency: ["google/api/annotations.proto", "protogin/options.proto"]
+options { go_package: "example.com/test/v1;testv1" }
+message_type { name: "Resp" }
+`
+
+// generate 按默认参数生成 metaProto 的代码，返回生成的文件内容，插件报告错误时返回错误信息
+func generate(t *testing.T, fields, services string) (content, errMsg string) {
+	t.Helper()
+	return generateWith(t, DefaultOptions(), fields, services)
+}
+
+// generateWith 与 generate 相同，但使用指定的插件参数
+func generateWith(t *testing.T, opts *Options, fields, services string) (content, errMsg string) {
+	t.Helper()
+	text := metaProto + `message_type { name: "Req" field { name: "id" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING json_name: "id" } ` + fields + " }\n" + services
+	gen, err := protogen.Options{}.New(testproto.Request(t, text))
+	if err != nil {
+		t.Fatalf("new plugin: %v", err)
+	}
+	GenerateFile(gen, gen.Files[len(gen.Files)-1], opts)
+	resp := gen.Response()
+	if resp.Error != nil {
+		return "", resp.GetError()
+	}
+	if len(resp.File) != 1 {
+		t.Fatalf("generated %d files, want 1", len(resp.File))
+	}
+	return resp.File[0].GetContent(), ""
+}
+
+// metaField 返回以 (protogin.field) 选项 opt（prototext）绑定的字段，typ 为 FieldDescriptorProto.Type 的名称
+func metaField(name string, number int, typ, opt string) string {
+	return `field { name: "` + name + `" number: ` + strconv.Itoa(number) + ` label: LABEL_OPTIONAL type: ` + typ + ` options { [protogin.field] { ` + opt + ` } } }`
+}
+
+// getService 只有一个 GET 方法的服务，methodOpt 为 (protogin.method) 选项的 prototext
+func getService(path, methodOpt string) string {
+	opts := `[google.api.http] { get: "` + path + `" }`
+	if methodOpt != "" {
+		opts += ` [protogin.method] { ` + methodOpt + ` }`
+	}
+	return `service { name: "Svc" method { name: "Get" input_type: ".test.v1.Req" output_type: ".test.v1.Resp" options { ` + opts + ` } } }`
+}
+
+func TestResolveMetaParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields string
+		want   []string // 生成代码中应当出现的片段
+		err    string
+	}{
+		{
+			name:   "header and cookie",
+			fields: metaField("request_id", 2, "TYPE_STRING", `header: "X-Request-Id"`) + metaField("session", 3, "TYPE_STRING", `cookie: "session_id"`) + metaField("page", 4, "TYPE_INT32", `header: "X-Page"`),
+			want: []string{
+				`runtime.ClearFields(&in, "request_id", "session", "page")`,
+				`runtime.Header(c, "X-Request-Id")`,
+				`runtime.Cookie(c, "session_id")`,
+				`runtime.NewBindError("header", "X-Page", err)`,
+			},
+		},
+		{
+			name:   "token characters",
+			fields: metaField("trace", 2, "TYPE_STRING", `header: "X-B3_Trace.Id!#$%&'*+^`+"`"+`|~"`),
+			want:   []string{`runtime.Header(c, "X-B3_Trace.Id!#$%&'*+^` + "`" + `|~")`},
+		},
+		{
+			name:   "quote in header name",
+			fields: metaField("request_id", 2, "TYPE_STRING", `header: "X-\"Id"`),
+			err:    `field "request_id": header name "X-\"Id" is not a valid token`,
+		},
+		{
+			name:   "space in header name",
+			fields: metaField("request_id", 2, "TYPE_STRING", `header: "X Request"`),
+			err:    `field "request_id": header name "X Request" is not a valid token`,
+		},
+		{
+			name:   "non-ASCII header name",
+			fields: metaField("request_id", 2, "TYPE_STRING", `header: "X-请求"`),
+			err:    `is not a valid token`,
+		},
+		{
+			name:   "separator in cookie name",
+			fields: metaField("session", 2, "TYPE_STRING", `cookie: "a=b"`),
+			err:    `field "session": cookie name "a=b" is not a valid token`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, errMsg := generate(t, tt.fields, getService("/v1/items/{id}", ""))
+			if tt.err != "" {
+				if !strings.Contains(errMsg, tt.err) {
+					t.Fatalf("error %q, want %q", errMsg, tt.err)
+				}
+				return
+			}
+			if errMsg != "" {
+				t.Fatalf("unexpected error: %s", errMsg)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(content, want) {
+					t.Errorf("generated code does not contain %s", want)
+				}
+			}
+		})
+	}
+}
+
+// TestGenerateQuotesLiterals 选项和路由中的字符串在生成代码中按 Go 字面量转义
+func TestGenerateQuotesLiterals(t *testing.T) {
+	content, errMsg := generate(t, "", getService(`/v1/items/{id=shelves/*/\"x\\y\"}`, `cache_ttl { seconds: 60 }`))
+	if errMsg != "" {
+		t.Fatalf("unexpected error: %s", errMsg)
+	}
+	for _, want := range []string{
+		`runtime.MustPattern("shelves/*/\"x\\y\"")`,
+		`c.Header("Cache-Control", "max-age=60")`,
+		`s.opts.Routes.Handle(s.router, "GET", "/v1/items/shelves/:id/\"x\\y\"", "", s.Get_0, s.opts.ErrorHandler)`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("generated code does not contain %s", want)
+		}
+	}
+}
@@ -1,25 +1,38 @@
 package generator
 
 import (
+	"fmt"
+	"strings"
+
 	"google.golang.org/genproto/googleapis/api/annotations"
 	"google.golang.org/protobuf/compiler/protogen"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/JarrettGuo/protogin/runtime"
 )
 
 const (
-	ginPkg    = protogen.GoImportPath("github.com/gin-gonic/gin")
-	httpPkg   = protogen.GoImportPath("net/http")
-	codesPkg  = protogen.GoImportPath("google.golang.org/grpc/codes")
-	statusPkg = protogen.GoImportPath("google.golang.org/grpc/status")
-	jsonPkg   = protogen.GoImportPath("encoding/json")
-)
+	ginPkg  = protogen.GoImportPath("github.com/gin-gonic/gin")
+	httpPkg = protogen.GoImportPath("net/http")
+
+	contextPkg = protogen.GoImportPath("context")
+	timePkg    = protogen.GoImportPath("time")
+
+	runtimePkg = protogen.GoImportPath("github.com/JarrettGuo/protogin/runtime")
 
-var methodSets = make(map[string]int)
+	// httpBodyMessage 原样传输请求体和响应体的消息类型，与生成代码调用的 runtime 使用同一个定义
+	httpBodyMessage = runtime.HTTPBodyMessage
+)
 
-func GenerateFile(gen *protogen.Plugin, file *protogen.File) *protogen.GeneratedFile {
+func GenerateFile(gen *protogen.Plugin, file *protogen.File, opts *Options) *protogen.GeneratedFile {
 	if len(file.Services) == 0 {
 		return nil
 	}
+	if err := opts.check(); err != nil {
+		gen.Error(err)
+		return nil
+	}
 
 	filename := file.GeneratedFilenamePrefix + "_gin.pb.go"
 	g := gen.NewGeneratedFile(filename, file.GoImportPath)
@@ -29,49 +42,106 @@ func GenerateFile(gen *protogen.Plugin, file *protogen.File) *protogen.Generated
 	g.P("package ", file.GoPackageName)
 
 	for _, service := range file.Services {
-		genService(file, g, service)
+		if err := genService(file, g, service, opts); err != nil {
+			gen.Error(err)
+			return nil
+		}
 	}
 
 	return g
 }
 
-func genService(file *protogen.File, g *protogen.GeneratedFile, s *protogen.Service) {
+func genService(file *protogen.File, g *protogen.GeneratedFile, s *protogen.Service, opts *Options) error {
 	// HTTP Server
 	sd := &service{
-		Name:      s.GoName,
-		FullName:  string(s.Desc.FullName()),
-		GinPkg:    g.QualifiedGoIdent(ginPkg.Ident("")),
-		HTTPPkg:   g.QualifiedGoIdent(httpPkg.Ident("")),
-		CodesPkg:  g.QualifiedGoIdent(codesPkg.Ident("")),
-		StatusPkg: g.QualifiedGoIdent(statusPkg.Ident("")),
-		JSONPkg:   g.QualifiedGoIdent(jsonPkg.Ident("")),
+		Name:     s.GoName,
+		FullName: string(s.Desc.FullName()),
+		GinPkg:   g.QualifiedGoIdent(ginPkg.Ident("")),
+		HTTPPkg:  g.QualifiedGoIdent(httpPkg.Ident("")),
+
+		RuntimePkg: g.QualifiedGoIdent(opts.runtimePkg().Ident("")),
+
+		Envelope: opts.Envelope,
+		StdJSON:  opts.JSON == "std",
 	}
 
+	// 每个 rpc 方法的 http 绑定计数，保证处理函数编号在服务内稳定
+	methodSets := make(map[string]int)
 	for _, method := range s.Methods {
-		sd.Methods = append(sd.Methods, genMethod(method)...)
+		methods, err := genMethod(g, method, methodSets, opts)
+		if err != nil {
+			return err
+		}
+		sd.Methods = append(sd.Methods, methods...)
 	}
 
-	text := sd.execute()
+	text, err := sd.execute()
+	if err != nil {
+		return fmt.Errorf("%s: service %s: %v", file.Desc.Path(), s.Desc.Name(), err)
+	}
 	g.P(text)
+	return nil
 }
 
-func genMethod(m *protogen.Method) []*method {
+func genMethod(g *protogen.GeneratedFile, m *protogen.Method, methodSets map[string]int, opts *Options) ([]*method, error) {
+	rules, err := httpRules(m, opts)
+	if err != nil {
+		return nil, methodError(m, err)
+	}
+
 	var methods []*method
+	for _, rule := range rules {
+		md, err := buildHTTPRule(g, m, rule, methodSets, opts)
+		if err != nil {
+			return nil, methodError(m, err)
+		}
+		methods = append(methods, md)
+	}
+	return methods, nil
+}
 
+// httpRules 返回方法的 google.api.http 注解及其 additional_bindings，每个绑定都生成独立的处理函数
+// （嵌套的 additional_bindings 按规范忽略）
+//
+// 没有注解的一元方法按 unannotated 参数处理：skip 不生成，default 映射为 POST /package.Service/Method
+// 且整个请求消息来自请求体，error 报错
+func httpRules(m *protogen.Method, opts *Options) ([]*annotations.HttpRule, error) {
 	rule, ok := proto.GetExtension(m.Desc.Options(), annotations.E_Http).(*annotations.HttpRule)
 	if rule != nil && ok {
-		methods = append(methods, buildHTTPRule(m, rule))
-		// 如果需要支持 additional_bindings，取消下面的注释
-		// for _, bind := range rule.AdditionalBindings {
-		//     methods = append(methods, buildHTTPRule(m, bind))
-		// }
-		return methods
+		return append([]*annotations.HttpRule{rule}, rule.AdditionalBindings...), nil
 	}
+	if m.Desc.IsStreamingClient() || m.Desc.IsStreamingServer() {
+		return nil, nil
+	}
+
+	switch opts.Unannotated {
+	case "default":
+		return []*annotations.HttpRule{{
+			Pattern: &annotations.HttpRule_Post{Post: fmt.Sprintf("/%s/%s", m.Parent.Desc.FullName(), m.Desc.Name())},
+			Body:    "*",
+		}}, nil
+	case "error":
+		return nil, fmt.Errorf("missing google.api.http annotation (unannotated=error)")
+	}
+	return nil, nil
+}
+
+// methodError 为错误加上 proto 文件中的位置以及服务和方法名，作为 protoc 的错误信息
+func methodError(m *protogen.Method, err error) error {
+	return fmt.Errorf("%s: %s.%s: %v", position(m), m.Parent.Desc.Name(), m.Desc.Name(), err)
+}
 
-	return methods
+// position 返回方法在 proto 文件中的位置，如 api/v1/api.proto:42:3，没有源码信息时只返回文件名
+func position(m *protogen.Method) string {
+	file := m.Desc.ParentFile()
+	loc := file.SourceLocations().ByDescriptor(m.Desc)
+	if len(loc.Path) == 0 {
+		return file.Path()
+	}
+	return fmt.Sprintf("%s:%d:%d", file.Path(), loc.StartLine+1, loc.StartColumn+1)
 }
 
-func buildHTTPRule(m *protogen.Method, rule *annotations.HttpRule) *method {
+func buildHTTPRule(g *protogen.GeneratedFile, m *protogen.Method, rule *annotations.HttpRule, methodSets map[string]int, opts *Options) (*method, error) {
 	var path, method string
 	switch pattern := rule.Pattern.(type) {
 	case *annotations.HttpRule_Get:
@@ -92,25 +162,296 @@ func buildHTTPRule(m *protogen.Method, rule *annotations.HttpRule) *method {
 	case *annotations.HttpRule_Custom:
 		path = pattern.Custom.Path
 		method = pattern.Custom.Kind
+		if !isValidCustomKind(method) {
+			return nil, fmt.Errorf("invalid custom http kind %q, must be uppercase letters like SEARCH", method)
+		}
+	default:
+		return nil, fmt.Errorf("http rule has no pattern")
+	}
+
+	md, err := buildMethodDesc(g, m, method, path, methodSets, opts)
+	if err == nil && method == "GET" && rule.Body != "" {
+		err = fmt.Errorf("GET binding cannot have a body")
+	}
+	if err == nil {
+		err = resolveBody(m, md, rule.Body)
+	}
+	if err == nil {
+		err = resolveResponseBody(m, md, rule.ResponseBody)
+	}
+	if err == nil {
+		err = resolveStreaming(m, md)
+	}
+	if err == nil {
+		err = resolveMetaParams(g, m, md, opts.runtimePkg())
+	}
+	if err == nil {
+		err = applyMethodOptions(g, m, md, opts)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %v", method, path, err)
 	}
+	return md, nil
+}
+
+// resolveResponseBody 解析 HttpRule.response_body：为空时返回整个响应消息，
+// 否则只返回响应消息中的该顶层字段
+func resolveResponseBody(m *protogen.Method, md *method, responseBody string) error {
+	md.ResponseBody = responseBody
+	if responseBody == "" {
+		// 服务端流式的 HttpBody 由 runtime.ServerStream 处理
+		md.RawResponse = !m.Desc.IsStreamingServer() && m.Output.Desc.FullName() == httpBodyMessage
+		return nil
+	}
+
+	for _, f := range m.Output.Fields {
+		if string(f.Desc.Name()) == responseBody {
+			md.ResponseField = f.GoName
+			return nil
+		}
+	}
+	return fmt.Errorf("response_body field %q not found in %s", responseBody, m.Output.Desc.FullName())
+}
 
-	md := buildMethodDesc(m, method, path)
-	md.Body = rule.Body
-	return md
+// resolveStreaming 检查流式方法的绑定：
+//   - 服务端流式方法的每条响应消息按 SSE 事件或 NDJSON 行写入，不支持 response_body
+//   - 客户端流式方法从请求体中逐条读取请求消息，因此必须使用 body: "*"
+//   - 双向流式方法通过 WebSocket 升级请求建立连接，因此必须绑定到不带 body 的 GET
+//
+// 客户端流式和双向流式方法的请求消息都来自流，不能有路径参数
+func resolveStreaming(m *protogen.Method, md *method) error {
+	client, server := m.Desc.IsStreamingClient(), m.Desc.IsStreamingServer()
+	switch {
+	case client && server:
+		if md.Method != "GET" || md.Body != "" {
+			return fmt.Errorf("bidi-streaming methods must be bound to GET without body")
+		}
+	case client:
+		if md.Body != "*" {
+			return fmt.Errorf(`client-streaming methods require body "*"`)
+		}
+	}
+	if client && len(md.PathParams) > 0 {
+		return fmt.Errorf("path parameters are not supported for %s methods", streamingKind(m))
+	}
+	if server && md.ResponseBody != "" {
+		return fmt.Errorf("response_body is not supported for streaming methods")
+	}
+	md.ClientStreaming, md.ServerStreaming = client, server
+	return nil
 }
 
-func buildMethodDesc(m *protogen.Method, httpMethod string, path string) *method {
+// streamingKind 流式方法的类型，用于错误信息
+func streamingKind(m *protogen.Method) string {
+	switch client, server := m.Desc.IsStreamingClient(), m.Desc.IsStreamingServer(); {
+	case client && server:
+		return "bidi-streaming"
+	case client:
+		return "client-streaming"
+	case server:
+		return "server-streaming"
+	}
+	return "unary"
+}
+
+// isValidCustomKind 自定义 HTTP 方法只能由大写字母组成（gin 的限制），如 SEARCH、PURGE
+func isValidCustomKind(kind string) bool {
+	if kind == "" {
+		return false
+	}
+	for _, r := range kind {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// resolveBody 解析 HttpRule.body：
+// * 表示整个请求消息来自请求体；字段名表示请求体只映射到该顶层字段，其余字段来自 query；
+// 为空表示没有请求体，除路径参数外的字段全部来自 query
+func resolveBody(m *protogen.Method, md *method, body string) error {
+	md.Body = body
+	if body == "" {
+		return nil
+	}
+	if body == "*" {
+		md.RawBody = m.Input.Desc.FullName() == httpBodyMessage
+		return nil
+	}
+
+	for _, f := range m.Input.Fields {
+		if string(f.Desc.Name()) == body {
+			if f.Oneof != nil && !f.Oneof.Desc.IsSynthetic() {
+				return fmt.Errorf("oneof field %q cannot be used as body", body)
+			}
+			md.BodyField = f.GoName
+			md.RawBody = f.Message != nil && !f.Desc.IsList() && f.Message.Desc.FullName() == httpBodyMessage
+			return nil
+		}
+	}
+	return fmt.Errorf("body field %q not found in %s", body, m.Input.Desc.FullName())
+}
+
+func buildMethodDesc(g *protogen.GeneratedFile, m *protogen.Method, httpMethod string, path string, methodSets map[string]int, opts *Options) (*method, error) {
 	defer func() { methodSets[m.GoName]++ }()
 
 	md := &method{
 		Name:     m.GoName,
 		Num:      methodSets[m.GoName],
-		Request:  m.Input.GoIdent.GoName,
-		Response: m.Output.GoIdent.GoName,
+		Naming:   opts.HandlerNaming,
+		Request:  g.QualifiedGoIdent(m.Input.GoIdent),
+		Template: opts.RoutePrefix + serviceOptions(m.Parent).GetBasePath() + path,
 		Path:     path,
 		Method:   httpMethod,
 	}
+	// 一元方法的处理函数不引用响应类型，只在流式方法中加上包名，避免生成未使用的导入
+	if m.Desc.IsStreamingClient() || m.Desc.IsStreamingServer() {
+		md.Response = g.QualifiedGoIdent(m.Output.GoIdent)
+	}
+	if md.HandlerName() == "RegisterService" {
+		return nil, fmt.Errorf("handler name %q conflicts with the generated RegisterService method, use handler_naming=index", md.HandlerName())
+	}
+
+	if err := md.initPathParams(); err != nil {
+		return nil, err
+	}
+	basePath := serviceOptions(m.Parent).GetBasePath()
+	if !isLiteralPrefix(basePath) {
+		return nil, fmt.Errorf(`bad (protogin.service).base_path %q: want a literal path like "/api/v1"`, basePath)
+	}
+	md.Path = opts.RoutePrefix + basePath + md.Path
 
-	md.initPathParams()
-	return md
+	// 同一字段或 gin 参数名在模板中出现多次时，gin 只能取到其中一个值
+	fields := make(map[string]bool)
+	params := make(map[string]bool)
+	for _, p := range md.PathParams {
+		if fields[p.Name] {
+			return nil, fmt.Errorf("field %q is bound more than once", p.Name)
+		}
+		fields[p.Name] = true
+		for _, param := range p.Params {
+			if params[param] {
+				return nil, fmt.Errorf("route parameter %q is used more than once", param)
+			}
+			params[param] = true
+		}
+
+		if err := resolvePathParam(g, m.Input, p, opts.runtimePkg()); err != nil {
+			return nil, err
+		}
+	}
+	return md, nil
+}
+
+// resolvePathParam 根据请求消息的描述符解析字段路径（如 user.id），
+// 得到赋值表达式以及沿途需要初始化的中间消息
+func resolvePathParam(g *protogen.GeneratedFile, msg *protogen.Message, p *pathParam, runtimePkg protogen.GoImportPath) error {
+	var field *protogen.Field
+	expr := "in"
+	names := strings.Split(p.Name, ".")
+	for i, name := range names {
+		field = nil
+		for _, f := range msg.Fields {
+			if string(f.Desc.Name()) == name {
+				field = f
+				break
+			}
+		}
+		if field == nil {
+			return fmt.Errorf("field %q not found in %s", name, msg.Desc.FullName())
+		}
+		if field.Oneof != nil && !field.Oneof.Desc.IsSynthetic() {
+			return fmt.Errorf("oneof field %q in %s cannot be bound", name, msg.Desc.FullName())
+		}
+
+		expr += "." + field.GoName
+		if i == len(names)-1 {
+			break
+		}
+
+		if field.Message == nil || field.Desc.IsList() || field.Desc.IsMap() {
+			return fmt.Errorf("field %q in %s is not a singular message", name, msg.Desc.FullName())
+		}
+		p.Parents = append(p.Parents, &pathParent{
+			Field: expr,
+			Type:  g.QualifiedGoIdent(field.Message.GoIdent),
+		})
+		msg = field.Message
+	}
+
+	if p.Pattern != "" && field.Desc.Kind() != protoreflect.StringKind {
+		return fmt.Errorf("field %q with pattern %q must be a string", p.Name, p.Pattern)
+	}
+
+	p.Field = expr
+	return resolvePathConvert(g, field, p, runtimePkg)
+}
+
+// wellKnownConverters 路径参数支持的 well-known 消息类型及对应的 runtime 转换函数
+var wellKnownConverters = map[protoreflect.FullName]string{
+	"google.protobuf.Timestamp":   "Timestamp",
+	"google.protobuf.Duration":    "Duration",
+	"google.protobuf.StringValue": "StringValue",
+	"google.protobuf.BoolValue":   "BoolValue",
+	"google.protobuf.Int32Value":  "Int32Value",
+	"google.protobuf.Int64Value":  "Int64Value",
+	"google.protobuf.UInt32Value": "UInt32Value",
+	"google.protobuf.UInt64Value": "UInt64Value",
+	"google.protobuf.FloatValue":  "FloatValue",
+	"google.protobuf.DoubleValue": "DoubleValue",
+	"google.protobuf.BytesValue":  "BytesValue",
+}
+
+// resolvePathConvert 根据字段类型选择路径参数的 runtime 转换函数以及赋值时的类型转换
+func resolvePathConvert(g *protogen.GeneratedFile, field *protogen.Field, p *pathParam, runtimePkg protogen.GoImportPath) error {
+	if field.Desc.IsList() || field.Desc.IsMap() {
+		return fmt.Errorf("repeated field %q cannot be bound", p.Name)
+	}
+
+	var convert string
+	switch field.Desc.Kind() {
+	case protoreflect.StringKind:
+		if field.Desc.HasPresence() && p.Pattern == "" {
+			convert = "String"
+		}
+	case protoreflect.BoolKind:
+		convert = "Bool"
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		convert = "Int32"
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		convert = "Int64"
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		convert = "Uint32"
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		convert = "Uint64"
+	case protoreflect.FloatKind:
+		convert = "Float32"
+	case protoreflect.DoubleKind:
+		convert = "Float64"
+	case protoreflect.BytesKind:
+		convert = "Bytes"
+	case protoreflect.EnumKind:
+		convert = "Enum"
+		p.ConvertArgs = ", " + g.QualifiedGoIdent(protogen.GoIdent{
+			GoName:       field.Enum.GoIdent.GoName + "_value",
+			GoImportPath: field.Enum.GoIdent.GoImportPath,
+		})
+		p.Cast = g.QualifiedGoIdent(field.Enum.GoIdent)
+	case protoreflect.MessageKind:
+		name, ok := wellKnownConverters[field.Message.Desc.FullName()]
+		if !ok {
+			return fmt.Errorf("message field %q of type %s cannot be bound", p.Name, field.Message.Desc.FullName())
+		}
+		convert = name
+	default:
+		return fmt.Errorf("field %q of kind %s cannot be bound", p.Name, field.Desc.Kind())
+	}
+
+	if convert != "" {
+		p.Convert = g.QualifiedGoIdent(runtimePkg.Ident(convert))
+	}
+	// proto3 optional 标量字段为指针类型
+	p.Pointer = field.Desc.HasPresence() && field.Message == nil
+	return nil
 }
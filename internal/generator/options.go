@@ -0,0 +1,93 @@
+package generator
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+// Options 插件参数，通过 buf.gen.yaml 的 opt 或 protoc 的 --gin_opt 设置
+type Options struct {
+	Envelope      bool   // envelope=false 时默认使用 runtime.RawWriter，直接返回响应消息
+	JSON          string // json=protojson|std，请求体和响应的 JSON 编解码方式
+	RoutePrefix   string // route_prefix=/api，所有路由的公共前缀
+	Unannotated   string // unannotated=skip|default|error，没有 google.api.http 注解的方法的处理方式
+	RuntimeImport string // runtime_import=...，生成代码引用的 runtime 包，必须按 runtime.RoutePath 注册路由，见 Validate
+	HandlerNaming string // handler_naming=index|plain，处理函数的命名方式
+}
+
+// DefaultOptions 默认参数，与不传参数时的行为一致
+func DefaultOptions() *Options {
+	return &Options{
+		Envelope:      true,
+		JSON:          "protojson",
+		Unannotated:   "skip",
+		RuntimeImport: string(runtimePkg),
+		HandlerNaming: "index",
+	}
+}
+
+// ParamFunc 返回设置插件参数的函数，用作 protogen.Options.ParamFunc，未知参数和无法解析的取值返回错误
+func (o *Options) ParamFunc() func(name, value string) error {
+	var flags flag.FlagSet
+	flags.BoolVar(&o.Envelope, "envelope", o.Envelope, "wrap responses in the protogin envelope, false defaults to runtime.RawWriter")
+	flags.StringVar(&o.JSON, "json", o.JSON, "JSON codec for bodies and responses: protojson or std")
+	flags.StringVar(&o.RoutePrefix, "route_prefix", o.RoutePrefix, "prefix for every route, e.g. /api")
+	flags.StringVar(&o.Unannotated, "unannotated", o.Unannotated, "methods without google.api.http: skip, default or error")
+	flags.StringVar(&o.RuntimeImport, "runtime_import", o.RuntimeImport, "import path of the runtime package, which must register routes like runtime.RoutePath")
+	flags.StringVar(&o.HandlerNaming, "handler_naming", o.HandlerNaming, "handler names: index (GetUser_0) or plain (GetUser)")
+
+	return func(name, value string) error {
+		if flags.Lookup(name) == nil {
+			return fmt.Errorf("unknown parameter %q", name)
+		}
+		if err := flags.Set(name, value); err != nil {
+			return fmt.Errorf("bad value %q for parameter %q: %v", value, name, err)
+		}
+		return nil
+	}
+}
+
+// check 检查参数取值
+func (o *Options) check() error {
+	if err := oneOf("json", o.JSON, "protojson", "std"); err != nil {
+		return err
+	}
+	if err := oneOf("unannotated", o.Unannotated, "skip", "default", "error"); err != nil {
+		return err
+	}
+	if err := oneOf("handler_naming", o.HandlerNaming, "index", "plain"); err != nil {
+		return err
+	}
+	if !isLiteralPrefix(o.RoutePrefix) {
+		return fmt.Errorf(`bad value %q for parameter "route_prefix": want a literal path like "/api"`, o.RoutePrefix)
+	}
+	if o.RuntimeImport == "" {
+		return fmt.Errorf(`parameter "runtime_import" cannot be empty`)
+	}
+	return nil
+}
+
+// runtimePkg 生成代码引用的 runtime 包
+func (o *Options) runtimePkg() protogen.GoImportPath {
+	return protogen.GoImportPath(o.RuntimeImport)
+}
+
+// isLiteralPrefix 路由前缀为空，或以 / 开头、不以 / 结尾且不包含参数的字面路径
+func isLiteralPrefix(prefix string) bool {
+	if prefix == "" {
+		return true
+	}
+	return strings.HasPrefix(prefix, "/") && !strings.HasSuffix(prefix, "/") && !strings.ContainsAny(prefix, ":*{}")
+}
+
+func oneOf(param, value string, want ...string) error {
+	for _, w := range want {
+		if value == w {
+			return nil
+		}
+	}
+	return fmt.Errorf(`bad value %q for parameter %q: want one of "%s"`, value, param, strings.Join(want, `", "`))
+}
@@ -0,0 +1,162 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/JarrettGuo/protogin/internal/testproto"
+)
+
+func TestParamFunc(t *testing.T) {
+	tests := []struct {
+		name  string
+		param string // CodeGeneratorRequest.parameter，即 buf.gen.yaml 的 opt
+		want  Options
+		err   string
+	}{
+		{name: "defaults", want: *DefaultOptions()},
+		{
+			name:  "all parameters",
+			param: "envelope=false,json=std,route_prefix=/api,unannotated=error,runtime_import=example.com/rt,handler_naming=plain",
+			want:  Options{JSON: "std", RoutePrefix: "/api", Unannotated: "error", RuntimeImport: "example.com/rt", HandlerNaming: "plain"},
+		},
+		{name: "unknown parameter", param: "envelop=false", err: `unknown parameter "envelop"`},
+		{name: "invalid bool", param: "envelope=no", err: `bad value "no" for parameter "envelope": parse error`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions()
+			req := testproto.Request(t, testProto)
+			req.Parameter = proto.String(tt.param)
+			_, err := protogen.Options{ParamFunc: opts.ParamFunc()}.New(req)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if *opts != tt.want {
+				t.Errorf("options %+v, want %+v", *opts, tt.want)
+			}
+		})
+	}
+}
+
+func TestOptionsCheck(t *testing.T) {
+	tests := []struct {
+		name string
+		set  func(o *Options)
+		err  string // 为空表示检查通过
+	}{
+		{name: "defaults", set: func(*Options) {}},
+		{name: "json", set: func(o *Options) { o.JSON = "xml" }, err: `bad value "xml" for parameter "json": want one of "protojson", "std"`},
+		{name: "unannotated", set: func(o *Options) { o.Unannotated = "warn" }, err: `bad value "warn" for parameter "unannotated": want one of "skip", "default", "error"`},
+		{name: "handler_naming", set: func(o *Options) { o.HandlerNaming = "camel" }, err: `bad value "camel" for parameter "handler_naming": want one of "index", "plain"`},
+		{name: "route_prefix", set: func(o *Options) { o.RoutePrefix = "/api/v1" }},
+		{name: "route_prefix without leading slash", set: func(o *Options) { o.RoutePrefix = "api" }, err: `bad value "api" for parameter "route_prefix": want a literal path like "/api"`},
+		{name: "route_prefix with trailing slash", set: func(o *Options) { o.RoutePrefix = "/api/" }, err: `bad value "/api/" for parameter "route_prefix"`},
+		{name: "route_prefix root", set: func(o *Options) { o.RoutePrefix = "/" }, err: `bad value "/" for parameter "route_prefix"`},
+		{name: "route_prefix with a parameter", set: func(o *Options) { o.RoutePrefix = "/{tenant}" }, err: `bad value "/{tenant}" for parameter "route_prefix"`},
+		{name: "route_prefix with a gin parameter", set: func(o *Options) { o.RoutePrefix = "/:tenant" }, err: `bad value "/:tenant" for parameter "route_prefix"`},
+		{name: "empty runtime_import", set: func(o *Options) { o.RuntimeImport = "" }, err: `parameter "runtime_import" cannot be empty`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions()
+			tt.set(opts)
+			err := opts.check()
+			if tt.err == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("error %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+// TestGenerateOptions 插件参数改变生成的代码
+func TestGenerateOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		set  func(o *Options)
+		want []string // 生成代码中应当出现的片段
+		not  []string // 生成代码中不应出现的片段
+		err  string
+	}{
+		{
+			name: "defaults",
+			set:  func(*Options) {},
+			want: []string{`func (s *SvcHTTPServer) Get_0(c *gin.Context)`, `s.opts.Routes.Handle(s.router, "GET", "/v1/items/:id", "", s.Get_0, s.opts.ErrorHandler)`, `s.opts.Marshaler.Marshal(out)`},
+			not:  []string{`RawWriter`},
+		},
+		{
+			name: "route_prefix",
+			set:  func(o *Options) { o.RoutePrefix = "/api" },
+			want: []string{`s.opts.Routes.Handle(s.router, "GET", "/api/v1/items/:id", "", s.Get_0, s.opts.ErrorHandler)`},
+		},
+		{
+			name: "handler_naming=plain",
+			set:  func(o *Options) { o.HandlerNaming = "plain" },
+			want: []string{`func (s *SvcHTTPServer) Get(c *gin.Context)`, `"/v1/items/:id", "", s.Get, s.opts.ErrorHandler)`},
+			not:  []string{`Get_0`},
+		},
+		{
+			name: "envelope=false",
+			set:  func(o *Options) { o.Envelope = false },
+			want: []string{`opts = append([]runtime.ServerOption{runtime.WithResponseWriter(runtime.RawWriter{})}, opts...)`},
+		},
+		{
+			name: "json=std",
+			set:  func(o *Options) { o.JSON = "std" },
+			want: []string{`data := out`},
+			not:  []string{`s.opts.Marshaler`},
+		},
+		{
+			name: "runtime_import",
+			set:  func(o *Options) { o.RuntimeImport = "example.com/rt" },
+			want: []string{`"example.com/rt"`, `rt.ServerOption`},
+			not:  []string{`"github.com/JarrettGuo/protogin/runtime"`},
+		},
+		{
+			name: "invalid options",
+			set:  func(o *Options) { o.JSON = "xml" },
+			err:  `bad value "xml" for parameter "json"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions()
+			tt.set(opts)
+			content, errMsg := generateWith(t, opts, "", getService("/v1/items/{id}", ""))
+			if tt.err != "" {
+				if !strings.Contains(errMsg, tt.err) {
+					t.Fatalf("error %q, want %q", errMsg, tt.err)
+				}
+				return
+			}
+			if errMsg != "" {
+				t.Fatalf("unexpected error: %s", errMsg)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(content, want) {
+					t.Errorf("generated code does not contain %s", want)
+				}
+			}
+			for _, not := range tt.not {
+				if strings.Contains(content, not) {
+					t.Errorf("generated code contains %s", not)
+				}
+			}
+		})
+	}
+}
@@ -4,8 +4,9 @@ import (
 	"bytes"
 	_ "embed"
 	"fmt"
-	"html/template"
+	"strconv"
 	"strings"
+	"text/template"
 )
 
 //go:embed template.go.tpl
@@ -15,51 +16,290 @@ var tpl string
 type method struct {
 	Name     string // GetDemoName
 	Num      int    // 一个 rpc 方法可以对应多个http请求
+	Naming   string // 处理函数的命名方式，index 或 plain
 	Request  string // *Req
 	Response string // *Resp
 
 	// http rule
+	Template     string // google.api.http 中的原始路径模板
 	Path         string
-	PathParams   []string
+	PathParams   []*pathParam
+	Verb         string // 自定义动词，如 /v1/users/{id}:ban 中的 ban
 	Method       string
-	Body         string
-	ResponseBody string
-	JSONPkg      string
+	Body         string // 映射到请求体的字段，* 表示整个请求消息，为空表示没有请求体
+	ResponseBody string // 映射到响应体的响应字段，为空表示整个响应消息
+
+	ServerStreaming bool // 服务端流式方法，响应按 SSE 或 NDJSON 逐条写入
+	ClientStreaming bool // 客户端流式方法，请求体按 NDJSON 或长度前缀逐条读取；两者都为 true 时为双向流式方法，使用 WebSocket
+
+	RawBody     bool // 请求体原样写入 google.api.HttpBody（整个请求消息或 body 字段）
+	RawResponse bool // 响应消息为 google.api.HttpBody，按 content_type 原样返回 data
+
+	BodyField     string // body 字段的 Go 名称，json=std 时使用
+	ResponseField string // response_body 字段的 Go 名称，json=std 时使用
+
+	// protogin 选项
+	MetaParams    []*metaParam // 从请求头和 cookie 绑定的字段
+	SuccessStatus int          // 成功响应的状态码，0 表示 200
+	Timeout       string       // 超时时间的 Go 表达式，如 5 * time.Second
+	WithTimeout   string       // context.WithTimeout（已按需加上包名）
+	CacheControl  string       // 成功响应的 Cache-Control
+	MaxBodySize   int64        // 请求体的最大字节数，0 表示不限制
+	Writer        string       // 写入成功响应的 ResponseWriter 表达式
+	RouteOptions  string       // runtime.RouteOptions 字面量，没有中间件和权限范围时为空
+	Redact        string       // runtime.Redact（已按需加上包名），响应中没有需要清除的字段时为空
+	JSONPkg       string
+}
+
+// pathParam 路由参数与请求字段的映射
+type pathParam struct {
+	Name    string        // 模板中的字段路径，如 user.id
+	Params  []string      // 对应的 gin 路由参数名
+	Pattern string        // 变量的段模式，如 projects/*/books/*，简单变量为空
+	Field   string        // 赋值的 Go 表达式，如 in.User.Id
+	Parents []*pathParent // 赋值前需要初始化的中间消息
+
+	// 类型转换
+	Convert     string // runtime 转换函数，string 字段为空
+	ConvertArgs string // 转换函数的额外参数，如枚举的 _value 映射
+	Cast        string // 赋值时的类型转换，如枚举类型
+	Pointer     bool   // 字段是否为指针（proto3 optional）
+}
+
+// Param 简单变量对应的 gin 路由参数名
+func (p *pathParam) Param() string {
+	return p.Params[0]
+}
+
+// Value 赋值给请求字段的 Go 表达式，local 为保存转换结果的局部变量
+func (p *pathParam) Value(local string) string {
+	if p.Pattern == "" && p.Convert == "" {
+		return fmt.Sprintf("c.Param(%q)", p.Param())
+	}
+	if p.Cast != "" {
+		local = p.Cast + "(" + local + ")"
+	}
+	if p.Pointer {
+		if p.Cast != "" {
+			return fmt.Sprintf("%s.Enum()", local)
+		}
+		return "&" + local
+	}
+	return local
+}
+
+// ParamArgs 按顺序读取所有路由参数的 Go 表达式，作为 Pattern.Build 的参数
+func (p *pathParam) ParamArgs() string {
+	args := make([]string, 0, len(p.Params))
+	for _, param := range p.Params {
+		args = append(args, fmt.Sprintf("c.Param(%q)", param))
+	}
+	return strings.Join(args, ", ")
 }
 
+// metaParam 从请求头或 cookie 绑定的请求字段，类型转换与路径参数相同
+type metaParam struct {
+	*pathParam
+	Source string // header 或 cookie
+	Key    string // 请求头或 cookie 的名称
+	Lookup string // runtime.Header 或 runtime.Cookie（已按需加上包名）
+}
+
+// pathParent 字段路径上的中间消息
+type pathParent struct {
+	Field string // Go 表达式，如 in.User
+	Type  string // 消息类型（已按需加上包名）
+}
+
+// HandlerName 处理函数名：index 为 GetUser_0、GetUser_1，plain 为 GetUser、GetUser_1
 func (m *method) HandlerName() string {
+	if m.Naming == "plain" && m.Num == 0 {
+		return m.Name
+	}
 	return fmt.Sprintf("%s_%d", m.Name, m.Num)
 }
 
+// StatusCode 成功响应状态码的 Go 表达式
+func (m *method) StatusCode() string {
+	if m.SuccessStatus == 0 {
+		return "http.StatusOK"
+	}
+	return strconv.Itoa(m.SuccessStatus)
+}
+
 // HasPathParams 是否包含路由参数
 func (m *method) HasPathParams() bool {
 	return len(m.PathParams) > 0
 }
 
-// initPathParams 转换参数路由 {xx} --> :xx
-func (m *method) initPathParams() {
-	if m.Path == "" {
-		return
+// QueryFilter 不从 query 参数中解析的字段路径（路径参数、请求头和 cookie 字段以及请求体字段），作为 PopulateQueryParameters 的参数
+func (m *method) QueryFilter() string {
+	var filter []string
+	for _, p := range m.PathParams {
+		filter = append(filter, fmt.Sprintf("%q", p.Name))
+	}
+	for _, p := range m.MetaParams {
+		filter = append(filter, fmt.Sprintf("%q", p.Name))
+	}
+	if m.Body != "" && m.Body != "*" {
+		filter = append(filter, fmt.Sprintf("%q", m.Body))
+	}
+	if len(filter) == 0 {
+		return ""
+	}
+	return ", " + strings.Join(filter, ", ")
+}
+
+// HasPatterns 是否包含需要在运行时重建的通配变量
+func (m *method) HasPatterns() bool {
+	for _, p := range m.PathParams {
+		if p.Pattern != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// initPathParams 将 google.api.http 路径模板转换为 gin 路由
+//
+//	{xx}                      --> :xx
+//	{xx=projects/*}           --> projects/:xx
+//	{xx=projects/*/books/*}   --> projects/:xx_1/books/:xx_2
+//	{xx=shelves/**}           --> shelves/*xx（gin 的 catch-all，只能出现在末尾）
+//	{xx}:verb                 --> :xx，动词由 runtime.Routes.Handle 在运行时分发
+//
+// 含通配段的变量在运行时通过 runtime.Pattern 重建完整的资源名
+func (m *method) initPathParams() error {
+	if !strings.HasPrefix(m.Path, "/") {
+		return fmt.Errorf("path must start with '/'")
+	}
+
+	segments, err := splitTemplate(m.Path)
+	if err != nil {
+		return err
+	}
+
+	// 拆出末尾的自定义动词，如 {user_id}:ban、users:search
+	last := segments[len(segments)-1]
+	if i := strings.LastIndexByte(last, ':'); i > 0 && !strings.Contains(last[i:], "}") {
+		m.Verb = last[i+1:]
+		if m.Verb == "" || strings.ContainsAny(m.Verb, "*{}=") {
+			return fmt.Errorf("invalid verb %q", m.Verb)
+		}
+		segments[len(segments)-1] = last[:i]
+	}
+
+	m.PathParams = []*pathParam{} // 重置参数列表
+	route := make([]string, 0, len(segments))
+	wildcards := 0
+	for _, seg := range segments {
+		switch {
+		case seg == "":
+			route = append(route, seg)
+		case seg[0] == '{':
+			if seg[len(seg)-1] != '}' {
+				return fmt.Errorf("invalid variable %q", seg)
+			}
+			name, pattern, hasPattern := strings.Cut(seg[1:len(seg)-1], "=")
+			if name == "" {
+				return fmt.Errorf("empty variable name in %q", seg)
+			}
+			if !hasPattern {
+				pattern = "*"
+			}
+
+			p := &pathParam{Name: name}
+			if pattern != "*" {
+				p.Pattern = pattern
+			}
+
+			parts := strings.Split(pattern, "/")
+			n := 0
+			for _, part := range parts {
+				if part == "*" || part == "**" {
+					n++
+				}
+			}
+			for _, part := range parts {
+				switch part {
+				case "*", "**":
+					param := name
+					if n > 1 {
+						param = fmt.Sprintf("%s_%d", name, len(p.Params)+1)
+					}
+					p.Params = append(p.Params, param)
+					if part == "**" {
+						route = append(route, "*"+param)
+					} else {
+						route = append(route, ":"+param)
+					}
+				default:
+					if part == "" || strings.ContainsAny(part, "*{}=") {
+						return fmt.Errorf("invalid segment %q in variable %q", part, name)
+					}
+					route = append(route, part)
+				}
+			}
+			m.PathParams = append(m.PathParams, p)
+		case seg == "*":
+			wildcards++
+			route = append(route, fmt.Sprintf(":wildcard_%d", wildcards))
+		case seg == "**":
+			wildcards++
+			route = append(route, fmt.Sprintf("*wildcard_%d", wildcards))
+		case len(seg) > 1 && seg[0] == ':':
+			// 已经是 :param 格式
+			route = append(route, seg)
+			m.PathParams = append(m.PathParams, &pathParam{Name: seg[1:], Params: []string{seg[1:]}})
+		default:
+			if strings.ContainsAny(seg, "*{}=") {
+				return fmt.Errorf("invalid segment %q", seg)
+			}
+			route = append(route, seg)
+		}
+	}
+
+	for i, r := range route {
+		if strings.HasPrefix(r, "*") && i != len(route)-1 {
+			return fmt.Errorf("'**' must be the last segment")
+		}
 	}
 
-	paths := strings.Split(m.Path, "/")
-	m.PathParams = []string{} // 重置参数列表
+	m.Path = strings.Join(route, "/")
+	return nil
+}
 
-	for i, p := range paths {
-		if p != "" {
-			// 处理 {param} 格式
-			if len(p) > 2 && p[0] == '{' && p[len(p)-1] == '}' {
-				paramName := p[1 : len(p)-1]
-				paths[i] = ":" + paramName
-				m.PathParams = append(m.PathParams, paramName)
-			} else if len(p) > 1 && p[0] == ':' {
-				// 已经是 :param 格式
-				m.PathParams = append(m.PathParams, p[1:])
+// splitTemplate 按 / 切分路径模板，变量 {...} 内部的 / 不参与切分
+func splitTemplate(path string) ([]string, error) {
+	var (
+		segments []string
+		depth    int
+		start    int
+	)
+	for i := 0; i < len(path); i++ {
+		switch path[i] {
+		case '{':
+			if depth > 0 {
+				return nil, fmt.Errorf("nested variable")
+			}
+			depth++
+		case '}':
+			if depth == 0 {
+				return nil, fmt.Errorf("unbalanced '}'")
+			}
+			depth--
+		case '/':
+			if depth == 0 {
+				segments = append(segments, path[start:i])
+				start = i + 1
 			}
 		}
 	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced '{'")
+	}
 
-	m.Path = strings.Join(paths, "/")
+	return append(segments, path[start:]), nil
 }
 
 // 在 template.go 中修改 service 结构体
@@ -70,15 +310,16 @@ type service struct {
 	MethodSet map[string]*method
 
 	// 添加包引用
-	GinPkg    string // 用于模板中的 gin 包引用
-	HTTPPkg   string // 用于模板中的 http 包引用
-	CodesPkg  string // 用于模板中的 codes 包引用
-	StatusPkg string // 用于模板中的 status 包引用
-	JSONPkg   string // 用于模板中的 json 包引用
+	GinPkg  string // 用于模板中的 gin 包引用
+	HTTPPkg string // 用于模板中的 http 包引用
+
+	RuntimePkg string // 用于模板中的 runtime 包引用
 
+	Envelope bool // 是否默认包装统一响应格式
+	StdJSON  bool // 是否使用 encoding/json 编解码请求体和响应
 }
 
-func (s *service) execute() string {
+func (s *service) execute() (string, error) {
 	if s.MethodSet == nil {
 		s.MethodSet = make(map[string]*method, len(s.Methods))
 
@@ -89,38 +330,18 @@ func (s *service) execute() string {
 
 	buf := new(bytes.Buffer)
 
-	// 注册 ProtoFieldName 函数（使用 protobuf 的命名规则）
-	tmpl, err := template.New("http").Funcs(template.FuncMap{
-		"ProtoFieldName": ProtoFieldName,
-	}).Parse(strings.TrimSpace(tpl))
-
+	tmpl, err := template.New("http").Parse(strings.TrimSpace(tpl))
 	if err != nil {
-		panic(err)
+		return "", err
 	}
 
 	if err := tmpl.Execute(buf, s); err != nil {
-		panic(err)
+		return "", err
 	}
 
-	return buf.String()
+	return buf.String(), nil
 }
 
 func (s *service) ServiceName() string {
 	return s.Name + "Server"
 }
-
-func ProtoFieldName(str string) string {
-	if str == "" {
-		return ""
-	}
-
-	str = strings.ReplaceAll(str, "-", "_")
-	parts := strings.Split(str, "_")
-	for i, part := range parts {
-		if len(part) > 0 {
-			parts[i] = strings.ToUpper(part[:1]) + strings.ToLower(part[1:])
-		}
-	}
-
-	return strings.Join(parts, "")
-}
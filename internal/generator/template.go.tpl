@@ -1,164 +1,152 @@
-// BizError 业务错误接口（用于单体应用）
-type BizError interface {
-	error
-	GetCode() string
-	GetStatus() int
-}
-
-// ErrorHandler 定义错误处理器类型
-type ErrorHandler func(c *gin.Context, err error)
-
-// HTTPServerOption 定义服务器选项
-type HTTPServerOption func(*{{.Name}}HTTPServer)
-
 // {{.Name}}HTTPServer HTTP服务器结构
 type {{.Name}}HTTPServer struct {
 	server {{.ServiceName}}
 	router gin.IRouter
-	errorHandler ErrorHandler
+	opts *{{$.RuntimePkg}}ServerOptions
 }
 
-// DefaultErrorHandler 默认错误处理器（支持单体和分布式）
-func DefaultErrorHandler(c *gin.Context, err error) {
-	if err == nil {
-		return
-	}
-
-	// 1. 优先检查是否是业务错误（单体应用场景）
-	if bizErr, ok := err.(BizError); ok {
-		c.JSON(bizErr.GetStatus(), gin.H{
-			"code": bizErr.GetCode(),
-			"message": bizErr.Error(),
-			"success": false,
-		})
-		return
-	}
-
-	// 2. 检查是否是 gRPC 错误（分布式场景）
-	if s, ok := status.FromError(err); ok {
-		httpStatus := grpcCodeToHTTP(s.Code())
-		c.JSON(httpStatus, gin.H{
-			"code": s.Code().String(),
-			"message": s.Message(),
-			"success": false,
-		})
-		return
-	}
-
-	// 3. 参数绑定错误
-	if _, ok := err.(*json.UnmarshalTypeError); ok {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": "INVALID_JSON",
-			"message": "请求格式错误",
-			"success": false,
-		})
-		return
-	}
-
-	// 4. 默认错误处理
-	c.JSON(http.StatusInternalServerError, gin.H{
-		"code": "INTERNAL_ERROR",
-		"message": err.Error(),
-		"success": false,
-	})
-}
-
-// grpcCodeToHTTP 将 gRPC 状态码转换为 HTTP 状态码
-func grpcCodeToHTTP(code codes.Code) int {
-	switch code {
-	case codes.OK:
-		return http.StatusOK
-	case codes.InvalidArgument:
-		return http.StatusBadRequest
-	case codes.NotFound:
-		return http.StatusNotFound
-	case codes.AlreadyExists:
-		return http.StatusConflict
-	case codes.PermissionDenied:
-		return http.StatusForbidden
-	case codes.Unauthenticated:
-		return http.StatusUnauthorized
-	case codes.ResourceExhausted:
-		return http.StatusTooManyRequests
-	case codes.FailedPrecondition, codes.OutOfRange:
-		return http.StatusBadRequest
-	case codes.Unimplemented:
-		return http.StatusNotImplemented
-	case codes.Unavailable:
-		return http.StatusServiceUnavailable
-	case codes.DeadlineExceeded:
-		return http.StatusGatewayTimeout
-	case codes.Canceled:
-		return 499 // Client Closed Request
-	case codes.Unknown, codes.Internal, codes.DataLoss:
-		return http.StatusInternalServerError
-	case codes.Aborted:
-		return http.StatusConflict
-	default:
-		return http.StatusInternalServerError
-	}
-}
-
-// WithErrorHandler 设置自定义错误处理器
-func WithErrorHandler(h ErrorHandler) HTTPServerOption {
-	return func(s *{{.Name}}HTTPServer) {
-		s.errorHandler = h
-	}
-}
-
-// Register{{.ServiceName}}HTTPServer 注册HTTP服务器
-func Register{{.ServiceName}}HTTPServer(srv {{.ServiceName}}, r gin.IRouter, opts ...HTTPServerOption) {
+// Register{{.ServiceName}}HTTPServer 注册HTTP服务器，错误处理器、请求体解码器等通过 {{$.RuntimePkg}}ServerOption 配置
+func Register{{.ServiceName}}HTTPServer(srv {{.ServiceName}}, r gin.IRouter, opts ...{{$.RuntimePkg}}ServerOption) {
+{{- if not .Envelope}}
+	// 生成时指定了 envelope=false，默认直接返回响应消息
+	opts = append([]{{$.RuntimePkg}}ServerOption{ {{- $.RuntimePkg}}WithResponseWriter({{$.RuntimePkg}}RawWriter{})}, opts...)
+{{end}}
 	s := {{.Name}}HTTPServer{
 		server: srv,
 		router: r,
-		errorHandler: DefaultErrorHandler,
-	}
-
-	// 应用选项
-	for _, opt := range opts {
-		opt(&s)
+		opts: {{$.RuntimePkg}}NewServerOptions(opts...),
 	}
 
 	s.RegisterService()
 }
-{{range .Methods}}
+{{range $m := .Methods}}{{if .HasPatterns}}
+var ({{range $i, $param := .PathParams}}{{if .Pattern}}
+	pattern_{{$.Name}}_{{$m.HandlerName}}_{{$i}} = {{$.RuntimePkg}}MustPattern({{printf "%q" .Pattern}}){{end}}{{end}}
+)
+{{end}}
 func (s *{{$.Name}}HTTPServer) {{.HandlerName}}(c *gin.Context) {
+{{- if .MaxBodySize}}
+	{{$.RuntimePkg}}LimitBody(c.Writer, c.Request, {{.MaxBodySize}})
+{{- end}}
+{{- if not .ClientStreaming}}
 	var in {{.Request}}
-{{if eq .Method "GET" "DELETE"}}
-	if err := c.ShouldBindQuery(&in); err != nil {
-		s.errorHandler(c, err)
+{{if ne .Body "*"}}
+	if err := {{$.RuntimePkg}}PopulateQueryParameters(&in, c.Request.URL.Query(){{.QueryFilter}}); err != nil {
+		s.opts.ErrorHandler(c, err)
 		return
 	}
-{{else if eq .Method "POST" "PUT" "PATCH"}}
-	if err := c.ShouldBindJSON(&in); err != nil {
-		s.errorHandler(c, err)
+{{end}}{{if .RawBody}}
+	if err := {{$.RuntimePkg}}DecodeHTTPBody(c.Request, &in, {{if eq .Body "*"}}""{{else}}{{printf "%q" .Body}}{{end}}); err != nil {
+		s.opts.ErrorHandler(c, err)
 		return
 	}
-{{else}}
-	if err := c.ShouldBind(&in); err != nil {
-		s.errorHandler(c, err)
+{{else if and (eq .Body "*") $.StdJSON}}
+	if err := {{$.RuntimePkg}}DecodeJSON(c.Request, &in); err != nil {
+		s.opts.ErrorHandler(c, err)
 		return
 	}
+{{else if eq .Body "*"}}
+	if err := s.opts.BodyDecoder.Decode(c.Request, &in); err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+{{else if and .Body $.StdJSON}}
+	if err := {{$.RuntimePkg}}DecodeJSON(c.Request, &in.{{.BodyField}}); err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+{{else if .Body}}
+	if err := s.opts.BodyDecoder.DecodeField(c.Request, &in, {{printf "%q" .Body}}); err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+{{end}}{{if .MetaParams}}
+	{{$.RuntimePkg}}ClearFields(&in{{range .MetaParams}}, {{printf "%q" .Name}}{{end}})
+{{end}}{{range $i, $h := .MetaParams}}
+	if v, ok := {{.Lookup}}(c, {{printf "%q" .Key}}); ok {
+{{- if .Convert}}
+		h{{$i}}, err := {{.Convert}}(v{{.ConvertArgs}})
+		if err != nil {
+			s.opts.ErrorHandler(c, {{$.RuntimePkg}}NewBindError({{printf "%q" .Source}}, {{printf "%q" .Key}}, err))
+			return
+		}
+		{{.Field}} = {{.Value (printf "h%d" $i)}}
+{{- else}}
+		{{.Field}} = v
+{{- end}}
+	}
 {{end}}
-{{if .HasPathParams}}{{range $param := .PathParams}}
-	in.{{ProtoFieldName $param}} = c.Param("{{$param}}")
-{{end}}{{end}}
+{{if .HasPathParams}}{{range $i, $param := .PathParams}}{{if .Pattern}}
+	p{{$i}}, err := pattern_{{$.Name}}_{{$m.HandlerName}}_{{$i}}.Build({{.ParamArgs}})
+	if err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
+{{else if .Convert}}
+	p{{$i}}, err := {{.Convert}}(c.Param({{printf "%q" .Param}}){{.ConvertArgs}})
+	if err != nil {
+		s.opts.ErrorHandler(c, {{$.RuntimePkg}}NewBindError("path parameter", {{printf "%q" .Name}}, err))
+		return
+	}
+{{end}}{{range .Parents}}
+	if {{.Field}} == nil {
+		{{.Field}} = &{{.Type}}{}
+	}{{end}}
+	{{.Field}} = {{.Value (printf "p%d" $i)}}
+{{end}}{{end}}{{end}}
 	ctx := c.Request.Context()
+{{- if .Timeout}}
+	ctx, cancel := {{.WithTimeout}}(ctx, {{.Timeout}})
+	defer cancel()
+{{- end}}
+{{- if and .ClientStreaming .ServerStreaming}}
+	stream := {{$.RuntimePkg}}NewBidiStreamingServer[{{.Request}}, {{.Response}}](c, ctx, s.opts)
+{{- if .Redact}}
+	stream.Redact = true
+{{- end}}
+	stream.Finish(s.server.{{.Name}}(stream))
+}
+{{else if .ServerStreaming}}
+	stream := {{$.RuntimePkg}}NewServerStreamingServer[{{.Response}}](c, ctx, s.opts)
+{{- if .Redact}}
+	stream.Redact = true
+{{- end}}
+	stream.Finish(s.server.{{.Name}}(&in, stream))
+}
+{{else}}
+{{- if .ClientStreaming}}
+	stream := {{$.RuntimePkg}}NewClientStreamingServer[{{.Request}}, {{.Response}}](c, ctx, s.opts)
+	out, err := stream.Finish(s.server.{{.Name}}(stream))
+{{- else}}
 	out, err := s.server.{{.Name}}(ctx, &in)
+{{- end}}
 	if err != nil {
-		s.errorHandler(c, err)
+		s.opts.ErrorHandler(c, err)
 		return
 	}
+{{- if .RawResponse}}
 
-	c.JSON(http.StatusOK, gin.H{
-		"code": "SUCCESS",
-		"message": "ok",
-		"data": out,
-		"success": true,
-	})
+{{if .CacheControl}}	c.Header("Cache-Control", {{printf "%q" .CacheControl}})
+{{end}}	{{$.RuntimePkg}}WriteHTTPBody(c, {{.StatusCode}}, out)
 }
+{{else}}
+{{- if .Redact}}
+	out = {{.Redact}}(out)
+{{- end}}
+
+{{if $.StdJSON}}	data := {{if .ResponseField}}out.Get{{.ResponseField}}(){{else}}out{{end}}
+{{else}}{{if .ResponseBody}}	data, err := s.opts.Marshaler.MarshalField(out, {{printf "%q" .ResponseBody}})
+{{else}}	data, err := s.opts.Marshaler.Marshal(out)
+{{end}}	if err != nil {
+		s.opts.ErrorHandler(c, err)
+		return
+	}
 {{end}}
+{{if .CacheControl}}	c.Header("Cache-Control", {{printf "%q" .CacheControl}})
+{{end}}	{{.Writer}}.WriteResponse(c, {{.StatusCode}}, data)
+}
+{{end}}{{end}}{{end}}
 func (s *{{.Name}}HTTPServer) RegisterService() {
-{{range .Methods}}	s.router.Handle("{{.Method}}", "{{.Path}}", s.{{.HandlerName}})
+{{range .Methods}}	s.opts.Routes.Handle(s.router, {{printf "%q" .Method}}, {{printf "%q" .Path}}, {{printf "%q" .Verb}}, {{if .RouteOptions}}s.opts.Wrap(s.{{.HandlerName}}, {{.RouteOptions}}){{else}}s.{{.HandlerName}}{{end}}, s.opts.ErrorHandler)
 {{end}}
 }
\ No newline at end of file
@@ -0,0 +1,95 @@
+package generator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestInitPathParams(t *testing.T) {
+	tests := []struct {
+		template string
+		path     string
+		verb     string
+		params   map[string][]string // 字段路径 -> gin 路由参数
+		patterns map[string]string   // 字段路径 -> 段模式
+	}{
+		{template: "/v1/users", path: "/v1/users"},
+		{template: "/v1/users/{id}", path: "/v1/users/:id", params: map[string][]string{"id": {"id"}}},
+		{template: "/v1/users/{user.id}", path: "/v1/users/:user.id", params: map[string][]string{"user.id": {"user.id"}}},
+		{template: "/v1/users/{id}:ban", path: "/v1/users/:id", verb: "ban", params: map[string][]string{"id": {"id"}}},
+		{template: "/v1/users:search", path: "/v1/users", verb: "search"},
+		{template: "/v1/users/{id}/books:list", path: "/v1/users/:id/books", verb: "list", params: map[string][]string{"id": {"id"}}},
+		{
+			template: "/v1/{name=projects/*}",
+			path:     "/v1/projects/:name",
+			params:   map[string][]string{"name": {"name"}},
+			patterns: map[string]string{"name": "projects/*"},
+		},
+		{
+			template: "/v1/{name=projects/*/books/*}:publish",
+			path:     "/v1/projects/:name_1/books/:name_2",
+			verb:     "publish",
+			params:   map[string][]string{"name": {"name_1", "name_2"}},
+			patterns: map[string]string{"name": "projects/*/books/*"},
+		},
+		{
+			template: "/v1/{name=shelves/**}",
+			path:     "/v1/shelves/*name",
+			params:   map[string][]string{"name": {"name"}},
+			patterns: map[string]string{"name": "shelves/**"},
+		},
+		{template: "/v1/*/users/**", path: "/v1/:wildcard_1/users/*wildcard_2"},
+	}
+	for _, tt := range tests {
+		m := &method{Path: tt.template}
+		if err := m.initPathParams(); err != nil {
+			t.Errorf("%s: %v", tt.template, err)
+			continue
+		}
+		if m.Path != tt.path || m.Verb != tt.verb {
+			t.Errorf("%s: path %q verb %q, want %q %q", tt.template, m.Path, m.Verb, tt.path, tt.verb)
+		}
+		params := map[string][]string{}
+		patterns := map[string]string{}
+		for _, p := range m.PathParams {
+			params[p.Name] = p.Params
+			if p.Pattern != "" {
+				patterns[p.Name] = p.Pattern
+			}
+		}
+		if tt.params == nil {
+			tt.params = map[string][]string{}
+		}
+		if tt.patterns == nil {
+			tt.patterns = map[string]string{}
+		}
+		if !reflect.DeepEqual(params, tt.params) || !reflect.DeepEqual(patterns, tt.patterns) {
+			t.Errorf("%s: params %v patterns %v, want %v %v", tt.template, params, patterns, tt.params, tt.patterns)
+		}
+	}
+}
+
+func TestInitPathParamsErrors(t *testing.T) {
+	tests := []struct {
+		template string
+		err      string
+	}{
+		{"v1/users", "path must start with '/'"},
+		{"/v1/users:", `invalid verb ""`},
+		{"/v1/users:se*rch", `invalid verb "se*rch"`},
+		{"/v1/{=projects/*}", "empty variable name"},
+		{"/v1/{name=projects//*}", `invalid segment "" in variable "name"`},
+		{"/v1/{name=shelves/**}/books", "'**' must be the last segment"},
+		{"/v1/**/books", "'**' must be the last segment"},
+		{"/v1/{name", "unbalanced"},
+		{"/v1/{a{b}}", "nested variable"},
+	}
+	for _, tt := range tests {
+		m := &method{Path: tt.template}
+		err := m.initPathParams()
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: error %v, want %q", tt.template, err, tt.err)
+		}
+	}
+}
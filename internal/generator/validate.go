@@ -0,0 +1,136 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/compiler/protogen"
+
+	"github.com/JarrettGuo/protogin/runtime"
+)
+
+// Validate 在生成代码之前检查所有待生成文件中的 http 绑定：
+// 路径参数是否对应请求消息中的标量字段、body 字段是否存在、GET 是否带有 body，
+// 以及同一 Go 包内所有服务的路由能否同时注册到 gin（冲突的路由在 gin 启动时会 panic）
+//
+// 所有问题合并为一个错误返回，每条都带有 proto 文件位置、服务和方法名
+//
+// 路由冲突总是按本仓库的 runtime.RoutePath 计算，与 runtime_import 无关：
+// runtime_import 指定的包（fork 或 vendor）必须保持 Routes.Handle 的注册方式，否则检查结果与实际注册不一致
+func Validate(gen *protogen.Plugin, opts *Options) error {
+	if err := opts.check(); err != nil {
+		return err
+	}
+
+	// 用真实的 gin 路由树检查冲突，关闭 debug 模式避免路由日志写入插件的 stdout
+	gin.SetMode(gin.ReleaseMode)
+
+	var errs []error
+	tables := make(map[protogen.GoImportPath]*routeTable)
+	for _, file := range gen.Files {
+		if !file.Generate || len(file.Services) == 0 {
+			continue
+		}
+
+		// 只用于解析类型名，不输出
+		g := gen.NewGeneratedFile(file.GeneratedFilenamePrefix+"_gin.validate.go", file.GoImportPath)
+		g.Skip()
+
+		table, ok := tables[file.GoImportPath]
+		if !ok {
+			table = &routeTable{engine: gin.New()}
+			tables[file.GoImportPath] = table
+		}
+
+		for _, s := range file.Services {
+			methodSets := make(map[string]int)
+			for _, m := range s.Methods {
+				rules, err := httpRules(m, opts)
+				if err != nil {
+					errs = append(errs, methodError(m, err))
+				}
+				for _, rule := range rules {
+					md, err := buildHTTPRule(g, m, rule, methodSets, opts)
+					if err == nil {
+						err = table.add(m, md)
+					}
+					if err != nil {
+						errs = append(errs, methodError(m, err))
+					}
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// route 一个 http 绑定向 gin 注册的路由
+type route struct {
+	method *protogen.Method
+	desc   *method
+	path   string // 实际注册的 gin 路由，见 runtime.RoutePath
+}
+
+func (r *route) String() string {
+	return fmt.Sprintf("%s %s (%s.%s at %s)", r.desc.Method, r.desc.Template,
+		r.method.Parent.Desc.Name(), r.method.Desc.Name(), position(r.method))
+}
+
+// routeTable 同一 Go 包内所有服务的路由，这些服务通常注册到同一个 gin.Engine 上
+type routeTable struct {
+	engine *gin.Engine
+	routes []*route
+}
+
+// add 按 runtime.Routes.Handle 的方式注册路由，gin 的 panic 转换为错误
+func (t *routeTable) add(m *protogen.Method, md *method) (err error) {
+	r := &route{method: m, desc: md, path: runtime.RoutePath(md.Path, md.Verb)}
+	for _, prev := range t.routes {
+		if prev.desc.Method != md.Method || prev.path != r.path {
+			continue
+		}
+		if prev.desc.Verb == md.Verb {
+			return fmt.Errorf("%s %s: route conflicts with %s", md.Method, md.Template, prev)
+		}
+		// 同一 gin 路由上的不同动词由 runtime 的 dispatcher 分发，只向 gin 注册一次
+		t.routes = append(t.routes, r)
+		return nil
+	}
+
+	defer func() {
+		v := recover()
+		if v == nil {
+			return
+		}
+		if prev := t.closest(r); prev != nil {
+			err = fmt.Errorf("%s %s: route conflicts with %s: %v", md.Method, md.Template, prev, v)
+		} else {
+			err = fmt.Errorf("%s %s: invalid route %q: %v", md.Method, md.Template, r.path, v)
+		}
+	}()
+	t.engine.Handle(md.Method, r.path, func(*gin.Context) {})
+	t.routes = append(t.routes, r)
+	return nil
+}
+
+// closest 返回与 r 方法相同且公共前缀最长的已注册路由，即 gin 报告冲突的那条路由
+func (t *routeTable) closest(r *route) *route {
+	var (
+		best    *route
+		longest = -1
+	)
+	for _, prev := range t.routes {
+		if prev.desc.Method != r.desc.Method {
+			continue
+		}
+		n := 0
+		for n < len(prev.path) && n < len(r.path) && prev.path[n] == r.path[n] {
+			n++
+		}
+		if n > longest {
+			best, longest = prev, n
+		}
+	}
+	return best
+}
@@ -0,0 +1,162 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/compiler/protogen"
+
+	"github.com/JarrettGuo/protogin/internal/testproto"
+)
+
+// testProto 测试服务所在的 proto 文件，services 为 service 描述的 prototext
+const testProto = `
+name: "test/v1/test.proto"
+package: "test.v1"
+syntax: "proto3"
+dependency: "google/api/annotations.proto"
+options { go_package: "example.com/test/v1;testv1" }
+message_type {
+  name: "Req"
+  field { name: "id" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING json_name: "id" }
+  field { name: "name" number: 2 label: LABEL_OPTIONAL type: TYPE_STRING json_name: "name" }
+}
+message_type { name: "Resp" }
+`
+
+// newPlugin 返回只生成 test/v1/test.proto 的插件，services 为追加到文件中的 service 定义
+func newPlugin(t *testing.T, services string) *protogen.Plugin {
+	t.Helper()
+	gen, err := protogen.Options{}.New(testproto.Request(t, testProto+services))
+	if err != nil {
+		t.Fatalf("new plugin: %v", err)
+	}
+	return gen
+}
+
+// rpc 返回一个以 http 规则（HttpRule 的 prototext）为注解的方法，rule 为空时不带注解
+func rpc(name, rule string) string {
+	m := `method { name: "` + name + `" input_type: ".test.v1.Req" output_type: ".test.v1.Resp"`
+	if rule != "" {
+		m += ` options { [google.api.http] { ` + rule + ` } }`
+	}
+	return m + " }\n"
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		services string
+		errs     []string // 期望的错误信息片段，为空表示检查通过
+	}{
+		{
+			name:     "valid bindings",
+			services: `service { name: "Svc" ` + rpc("Get", `get: "/v1/items/{id}"`) + rpc("Create", `post: "/v1/items" body: "*"`) + rpc("Search", `custom { kind: "SEARCH" path: "/v1/items" }`) + `}`,
+		},
+		{
+			name:     "verbs share a route",
+			services: `service { name: "Svc" ` + rpc("Ban", `post: "/v1/items/{id}:ban"`) + rpc("Mute", `post: "/v1/items/{id}:mute"`) + `}`,
+		},
+		{
+			name:     "GET with body",
+			services: `service { name: "Svc" ` + rpc("Get", `get: "/v1/items/{id}" body: "*"`) + `}`,
+			errs:     []string{`test/v1/test.proto: Svc.Get: GET /v1/items/{id}: GET binding cannot have a body`},
+		},
+		{
+			name:     "invalid custom kind",
+			services: `service { name: "Svc" ` + rpc("Search", `custom { kind: "search" path: "/v1/items" }`) + `}`,
+			errs:     []string{`Svc.Search: invalid custom http kind "search", must be uppercase letters like SEARCH`},
+		},
+		{
+			name:     "unknown path field",
+			services: `service { name: "Svc" ` + rpc("Get", `get: "/v1/items/{item_id}"`) + `}`,
+			errs:     []string{`Svc.Get: GET /v1/items/{item_id}:`},
+		},
+		{
+			name:     "conflicting parameter names",
+			services: `service { name: "Svc" ` + rpc("Get", `get: "/v1/items/{id}"`) + rpc("GetByName", `get: "/v1/items/{name}"`) + `}`,
+			errs:     []string{`Svc.GetByName: GET /v1/items/{name}: route conflicts with GET /v1/items/{id} (Svc.Get at test/v1/test.proto)`},
+		},
+		{
+			name:     "same route and verb",
+			services: `service { name: "Svc" ` + rpc("Ban", `post: "/v1/items/{id}:ban"`) + rpc("Block", `post: "/v1/items/{id}:ban"`) + `}`,
+			errs:     []string{`Svc.Block: POST /v1/items/{id}:ban: route conflicts with POST /v1/items/{id}:ban (Svc.Ban at test/v1/test.proto)`},
+		},
+		{
+			name: "conflict across services",
+			services: `service { name: "A" ` + rpc("Get", `get: "/v1/items/{id}"`) + `}
+service { name: "B" ` + rpc("Get", `get: "/v1/items/{id=**}"`) + `}`,
+			errs: []string{`B.Get: GET /v1/items/{id=**}: route conflicts with GET /v1/items/{id} (A.Get at test/v1/test.proto)`},
+		},
+		{
+			name:     "all problems are reported",
+			services: `service { name: "Svc" ` + rpc("Get", `get: "/v1/items/{id}" body: "*"`) + rpc("Search", `custom { kind: "se arch" path: "/v1/items" }`) + `}`,
+			errs:     []string{"Svc.Get: ", "Svc.Search: "},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(newPlugin(t, tt.services), DefaultOptions())
+			if len(tt.errs) == 0 {
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate passed, want errors %q", tt.errs)
+			}
+			for _, want := range tt.errs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Validate error:\n%v\nwant it to contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateUnannotated(t *testing.T) {
+	services := `service { name: "Svc" ` + rpc("Get", `get: "/v1/items/{id}"`) + rpc("Plain", "") + `}`
+
+	tests := []struct {
+		unannotated string
+		err         string
+		route       string // 期望生成的路由片段，为空表示不生成
+	}{
+		{unannotated: "skip"},
+		{unannotated: "default", route: `"POST", "/test.v1.Svc/Plain"`},
+		{unannotated: "error", err: "Svc.Plain: missing google.api.http annotation (unannotated=error)"},
+		{unannotated: "ignore", err: `bad value "ignore" for parameter "unannotated"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.unannotated, func(t *testing.T) {
+			opts := DefaultOptions()
+			opts.Unannotated = tt.unannotated
+			gen := newPlugin(t, services)
+
+			err := Validate(gen, opts)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("Validate error %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+
+			g := GenerateFile(gen, gen.Files[len(gen.Files)-1], opts)
+			content, err := g.Content()
+			if err != nil {
+				t.Fatalf("generated code: %v", err)
+			}
+			has := strings.Contains(string(content), "Plain")
+			if tt.route == "" && has {
+				t.Errorf("unannotated method was generated:\n%s", content)
+			}
+			if tt.route != "" && !strings.Contains(string(content), tt.route) {
+				t.Errorf("generated code does not register %s:\n%s", tt.route, content)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// Package testproto 为测试从 prototext 格式的 FileDescriptorProto 构造描述符
+package testproto
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// FileProto 解析 prototext 格式的 FileDescriptorProto
+func FileProto(t testing.TB, text string) *descriptorpb.FileDescriptorProto {
+	t.Helper()
+	fdp := &descriptorpb.FileDescriptorProto{}
+	if err := prototext.Unmarshal([]byte(text), fdp); err != nil {
+		t.Fatalf("unmarshal descriptor: %v", err)
+	}
+	return fdp
+}
+
+// Message 返回 text 描述的文件中名为 name 的顶层消息，依赖的文件从 protoregistry.GlobalFiles 中查找
+func Message(t testing.TB, text, name string) protoreflect.MessageDescriptor {
+	t.Helper()
+	fd, err := protodesc.NewFile(FileProto(t, text), protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatalf("new file: %v", err)
+	}
+	md := fd.Messages().ByName(protoreflect.Name(name))
+	if md == nil {
+		t.Fatalf("message %q not found in %s", name, fd.Path())
+	}
+	return md
+}
+
+// Request 返回只生成 text 描述的文件的插件请求，依赖的文件从 protoregistry.GlobalFiles 中查找并按依赖顺序放在前面
+func Request(t testing.TB, text string) *pluginpb.CodeGeneratorRequest {
+	t.Helper()
+	fdp := FileProto(t, text)
+	req := &pluginpb.CodeGeneratorRequest{FileToGenerate: []string{fdp.GetName()}}
+	seen := make(map[string]bool)
+	var add func(fd protoreflect.FileDescriptor)
+	add = func(fd protoreflect.FileDescriptor) {
+		if seen[fd.Path()] {
+			return
+		}
+		seen[fd.Path()] = true
+		imports := fd.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			add(imports.Get(i).FileDescriptor)
+		}
+		req.ProtoFile = append(req.ProtoFile, protodesc.ToFileDescriptorProto(fd))
+	}
+	for _, dep := range fdp.GetDependency() {
+		fd, err := protoregistry.GlobalFiles.FindFileByPath(dep)
+		if err != nil {
+			t.Fatalf("dependency %s: %v", dep, err)
+		}
+		add(fd)
+	}
+	req.ProtoFile = append(req.ProtoFile, fdp)
+	return req
+}
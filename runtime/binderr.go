@@ -0,0 +1,300 @@
+package runtime
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	perrors "github.com/JarrettGuo/protogin/errors"
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+// BindError 请求绑定错误，生成的处理函数在解析路径参数、query 参数、请求头、cookie 和请求体失败时返回
+//
+// BindError 实现了 BizError，ErrorStatus 按 Status 输出；经过 gRPC 时转换为带有 BadRequest 详情的状态
+type BindError struct {
+	// Status HTTP 状态码：400 请求格式或字段值错误，413 请求体过大，415 不支持的 Content-Type
+	Status int
+	// Source 出错的位置：request body、query parameter、form field、path parameter、header 或 cookie
+	Source string
+	// Field 出错的字段，如 user.age，无法确定时为空
+	Field string
+	// Line 和 Column 请求体中出错的位置（从 1 开始），无法确定时为 0
+	Line, Column int
+	// Message 返回给客户端的错误信息，如 invalid path parameter "author_id": want int64，不包含 Go 的错误文本
+	Message string
+	// Err 原始错误，只用于 errors.Unwrap 和日志，可以为 nil
+	Err error
+}
+
+func (e *BindError) Error() string {
+	return e.Message
+}
+
+func (e *BindError) Unwrap() error {
+	return e.Err
+}
+
+func (e *BindError) GetStatus() int {
+	return e.Status
+}
+
+// GetCode 返回 Status 对应的 gRPC 状态码名称（errors.CodeFromHTTPStatus）：
+// 400 和 415 为 InvalidArgument，413 为 ResourceExhausted
+func (e *BindError) GetCode() string {
+	return perrors.CodeFromHTTPStatus(e.Status).String()
+}
+
+// GRPCStatus 400 错误带有 BadRequest 详情，每个出错的字段一条
+func (e *BindError) GRPCStatus() *status.Status {
+	err := perrors.New(e.GetCode(), e.Error(), e.Status)
+	if e.Status == http.StatusBadRequest {
+		err = err.WithDetails(&errdetails.BadRequest{FieldViolations: e.violations()})
+	}
+	return err.GRPCStatus()
+}
+
+func (e *BindError) violations() []*errdetails.BadRequest_FieldViolation {
+	var ve validator.ValidationErrors
+	if errors.As(e.Err, &ve) {
+		vs := make([]*errdetails.BadRequest_FieldViolation, len(ve))
+		for i, fe := range ve {
+			vs[i] = &errdetails.BadRequest_FieldViolation{Field: fe.Namespace(), Description: validationMessage(fe), Reason: fe.Tag()}
+		}
+		return vs
+	}
+
+	field := e.Field
+	if field == "" && e.Source == sourceBody {
+		field = "body"
+	}
+	return []*errdetails.BadRequest_FieldViolation{{Field: field, Description: e.Error()}}
+}
+
+const sourceBody = "request body"
+
+// NewBindError 把绑定 source 中的 field 时的错误转换为 BindError，生成的处理函数用于路径参数、请求头和 cookie
+//
+// 请求体过大（http.MaxBytesError 和 multipart.ErrMessageTooLarge）为 413，其余为 400；json.UnmarshalTypeError 和 validator.ValidationErrors 的字段名优先于 field。
+// 错误信息按错误类型生成（如 *ConvertError 为 want int64），err 只通过 Unwrap 返回
+func NewBindError(source string, field string, err error) *BindError {
+	var be *BindError
+	if errors.As(err, &be) {
+		return be
+	}
+	be = &BindError{Status: http.StatusBadRequest, Source: source, Field: field, Err: err}
+
+	var (
+		maxBytes   *http.MaxBytesError
+		convErr    *ConvertError
+		syntaxErr  *json.SyntaxError
+		typeErr    *json.UnmarshalTypeError
+		numErr     *strconv.NumError
+		validation validator.ValidationErrors
+		reason     string
+	)
+	switch {
+	case errors.As(err, &maxBytes):
+		be.Status = http.StatusRequestEntityTooLarge
+		be.Message = fmt.Sprintf("request body exceeds %d bytes", maxBytes.Limit)
+		return be
+	case errors.Is(err, multipart.ErrMessageTooLarge):
+		be.Status = http.StatusRequestEntityTooLarge
+		be.Message = "request body is too large"
+		return be
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		reason = "unexpected end of input"
+	case errors.As(err, &convErr):
+		reason = convErr.Error()
+	case errors.As(err, &syntaxErr):
+		reason = "malformed JSON"
+	case errors.As(err, &typeErr):
+		if typeErr.Field != "" {
+			be.Field = typeErr.Field
+		}
+		reason = "want " + typeErr.Type.String()
+	case errors.As(err, &numErr):
+		reason = "want " + numberKind(numErr.Func)
+	case errors.As(err, &validation) && len(validation) > 0:
+		be.Field = validation[0].Namespace()
+		reason = validationMessage(validation[0])
+	}
+	be.Message = bindMessage(describe(source, be.Field), 0, 0, reason)
+	return be
+}
+
+func describe(source, field string) string {
+	if field == "" {
+		return source
+	}
+	return fmt.Sprintf("%s %q", source, field)
+}
+
+// bindMessage 返回 invalid <what> (line L:C): <reason> 格式的错误信息，line 为 0 或 reason 为空时省略对应部分
+func bindMessage(what string, line, column int, reason string) string {
+	msg := "invalid " + what
+	if line > 0 {
+		msg += fmt.Sprintf(" (line %d:%d)", line, column)
+	}
+	if reason != "" {
+		msg += ": " + reason
+	}
+	return msg
+}
+
+// validationMessage 返回校验失败的规则，如 fails "gte=18" validation
+func validationMessage(fe validator.FieldError) string {
+	rule := fe.Tag()
+	if fe.Param() != "" {
+		rule += "=" + fe.Param()
+	}
+	return fmt.Sprintf("fails %q validation", rule)
+}
+
+// numberKind 返回 strconv 解析函数期望的类型
+func numberKind(fn string) string {
+	switch fn {
+	case "ParseBool":
+		return "bool"
+	case "ParseUint":
+		return "unsigned integer"
+	case "ParseFloat":
+		return "number"
+	}
+	return "integer"
+}
+
+// protojsonPosition 和 protojsonField 匹配 protojson 错误中的位置和字段，如
+// proto: (line 1:10): invalid value for int32 field age: "x" 和 proto: (line 1:2): unknown field "foo"
+var (
+	protojsonPosition = regexp.MustCompile(`\(line (\d+):(\d+)\)`)
+	protojsonField    = regexp.MustCompile(`(unknown field|duplicate field|for (\w+) field) "?(\w+)"?`)
+)
+
+// invalidBody 把请求体的解码错误转换为 400 BindError，field 为请求体对应的字段（如 body 字段名或流中的第几条消息），
+// 可以为空。从 protojson 错误中解析出错的位置、字段名和期望的类型；data 为解码的内容，用于把 encoding/json 错误的偏移量转换为行列
+func invalidBody(field string, err error, data []byte) *BindError {
+	be, reason := parseBodyError(field, err, data)
+	be.Message = bindMessage(describe(sourceBody, be.Field), be.Line, be.Column, reason)
+	return be
+}
+
+// parseBodyError 与 invalidBody 相同，但不生成 Message，同时返回出错的原因，供需要调整字段和位置的调用方使用
+func parseBodyError(field string, err error, data []byte) (be *BindError, reason string) {
+	be = &BindError{Status: http.StatusBadRequest, Source: sourceBody, Field: field, Err: err}
+
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+		offset    int64 = -1
+	)
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+		reason = "malformed JSON"
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+		be.Field = joinField(field, typeErr.Field)
+		reason = "want " + typeErr.Type.String()
+	default:
+		msg := err.Error()
+		if m := protojsonPosition.FindStringSubmatch(msg); m != nil {
+			be.Line, _ = strconv.Atoi(m[1])
+			be.Column, _ = strconv.Atoi(m[2])
+		}
+		switch m := protojsonField.FindStringSubmatch(msg); {
+		case m == nil:
+		case m[2] != "":
+			be.Field = joinField(field, m[3])
+			reason = "want " + m[2]
+		default:
+			be.Field = joinField(field, m[3])
+			reason = m[1]
+		}
+		switch {
+		case reason != "":
+		case strings.Contains(msg, "syntax error"):
+			reason = "malformed JSON"
+		case strings.Contains(msg, "unexpected EOF"), errors.Is(err, io.ErrUnexpectedEOF):
+			reason = "unexpected end of input"
+		}
+	}
+	if offset >= 0 && data != nil {
+		// encoding/json 的错误信息中没有位置
+		be.Line, be.Column = position(data, offset)
+	}
+	return be, reason
+}
+
+func joinField(parent, name string) string {
+	if parent == "" || name == "" {
+		return parent + name
+	}
+	return parent + "." + name
+}
+
+// position 把字节偏移量转换为行列（从 1 开始）
+func position(data []byte, offset int64) (line, column int) {
+	offset = min(offset, int64(len(data)))
+	before := data[:offset]
+	line = bytes.Count(before, []byte("\n")) + 1
+	column = int(offset) - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
+
+// bodyTooLarge 请求体超过 http.MaxBytesReader 的限制时返回 413 BindError
+func bodyTooLarge(err error) (*BindError, bool) {
+	var maxBytes *http.MaxBytesError
+	if !errors.As(err, &maxBytes) && !errors.Is(err, multipart.ErrMessageTooLarge) {
+		return nil, false
+	}
+	return NewBindError(sourceBody, "", err), true
+}
+
+// checkContentType JSON 请求体的 Content-Type 必须为空、application/json 或 +json 后缀的类型，否则返回 415 BindError
+func checkContentType(r *http.Request) error {
+	ct := r.Header.Get("Content-Type")
+	if ct == "" {
+		return nil
+	}
+	mt, _, err := mime.ParseMediaType(ct)
+	switch {
+	case err != nil:
+	case mt == "application/json", strings.HasSuffix(mt, "+json"):
+		return nil
+	}
+	return &BindError{
+		Status:  http.StatusUnsupportedMediaType,
+		Source:  sourceBody,
+		Message: fmt.Sprintf("unsupported Content-Type %q, want application/json", ct),
+	}
+}
+
+// asBindError 把 ErrorHandler 收到的常见绑定错误（如服务实现中 gin 绑定返回的错误）转换为 BindError
+func asBindError(err error) (*BindError, bool) {
+	var (
+		syntaxErr  *json.SyntaxError
+		typeErr    *json.UnmarshalTypeError
+		numErr     *strconv.NumError
+		maxBytes   *http.MaxBytesError
+		validation validator.ValidationErrors
+	)
+	switch {
+	case errors.As(err, &numErr):
+		return NewBindError("parameter", "", err), true
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr), errors.As(err, &maxBytes),
+		errors.As(err, &validation), errors.Is(err, multipart.ErrMessageTooLarge), err == io.EOF:
+		return NewBindError(sourceBody, "", err), true
+	}
+	return nil, false
+}
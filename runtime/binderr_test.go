@@ -0,0 +1,222 @@
+package runtime
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/JarrettGuo/protogin/internal/testproto"
+)
+
+// checkBindError 比较 BindError 的状态码、字段、位置和错误信息
+func checkBindError(t *testing.T, be *BindError, status int, field string, line, column int, msg string) {
+	t.Helper()
+	if be.Status != status || be.Field != field || be.Line != line || be.Column != column || be.Message != msg {
+		t.Errorf("got %d %q %d:%d %q, want %d %q %d:%d %q",
+			be.Status, be.Field, be.Line, be.Column, be.Message, status, field, line, column, msg)
+	}
+}
+
+// TestInvalidBodyProtojson 固定当前 protojson 的错误文本及从中解析出的字段和位置，
+// protojson 的错误格式变化时这里会失败
+func TestInvalidBodyProtojson(t *testing.T) {
+	md := testproto.Message(t, queryProto, "Query")
+
+	tests := []struct {
+		name   string
+		body   string
+		parent string // invalidBody 的 field 参数，不为空时解码到 Query.Inner
+		raw    string // protojson 错误中应包含的文本
+		field  string
+		line   int
+		column int
+		msg    string
+	}{
+		{
+			name: "unknown field", body: `{"foo":1}`,
+			raw:   `(line 1:2): unknown field "foo"`,
+			field: "foo", line: 1, column: 2,
+			msg: `invalid request body "foo" (line 1:2): unknown field`,
+		},
+		{
+			name: "duplicate field", body: `{"name":"a","name":"b"}`,
+			raw:   `(line 1:13): duplicate field "name"`,
+			field: "name", line: 1, column: 13,
+			msg: `invalid request body "name" (line 1:13): duplicate field`,
+		},
+		{
+			name: "type mismatch", body: `{"pageSize":"x"}`,
+			raw:   `(line 1:13): invalid value for int32 field pageSize: "x"`,
+			field: "pageSize", line: 1, column: 13,
+			msg: `invalid request body "pageSize" (line 1:13): want int32`,
+		},
+		{
+			name: "type mismatch on a later line", body: "{\n  \"name\": 1\n}",
+			raw:   `(line 2:11): invalid value for string field name: 1`,
+			field: "name", line: 2, column: 11,
+			msg: `invalid request body "name" (line 2:11): want string`,
+		},
+		{
+			name: "type mismatch in a body field", body: `{"id":5}`, parent: "inner",
+			raw:   `(line 1:7): invalid value for string field id: 5`,
+			field: "inner.id", line: 1, column: 7,
+			msg: `invalid request body "inner.id" (line 1:7): want string`,
+		},
+		{
+			name: "syntax error", body: `{"name" "a"}`,
+			raw:  `syntax error (line 1:9)`,
+			line: 1, column: 9,
+			msg: `invalid request body (line 1:9): malformed JSON`,
+		},
+		{
+			name: "empty body", body: ``,
+			raw:  `syntax error (line 1:1)`,
+			line: 1, column: 1,
+			msg: `invalid request body (line 1:1): malformed JSON`,
+		},
+		{
+			name: "unexpected EOF", body: `{"name":`,
+			raw: `unexpected EOF`,
+			msg: `invalid request body: unexpected end of input`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := md
+			if tt.parent != "" {
+				target = md.Messages().ByName("Inner")
+			}
+			err := protojson.Unmarshal([]byte(tt.body), dynamicpb.NewMessage(target))
+			if err == nil || !strings.Contains(err.Error(), tt.raw) {
+				t.Fatalf("protojson error %v, want it to contain %q", err, tt.raw)
+			}
+			be := invalidBody(tt.parent, err, []byte(tt.body))
+			if be.Source != sourceBody || be.Err != err {
+				t.Errorf("source %q err %v", be.Source, be.Err)
+			}
+			checkBindError(t, be, http.StatusBadRequest, tt.field, tt.line, tt.column, tt.msg)
+		})
+	}
+}
+
+func TestInvalidBodyEncodingJSON(t *testing.T) {
+	var v struct {
+		Age int32 `json:"age"`
+	}
+
+	data := []byte("{\n  \"age\": \"x\"\n}")
+	be := invalidBody("", json.Unmarshal(data, &v), data)
+	checkBindError(t, be, http.StatusBadRequest, "age", 2, 13, `invalid request body "age" (line 2:13): want int32`)
+
+	data = []byte(`{"age": }`)
+	be = invalidBody("user", json.Unmarshal(data, &v), data)
+	checkBindError(t, be, http.StatusBadRequest, "user", 1, 10, `invalid request body "user" (line 1:10): malformed JSON`)
+
+	// 没有请求体内容时不计算位置
+	be = invalidBody("", json.Unmarshal(data, &v), nil)
+	checkBindError(t, be, http.StatusBadRequest, "", 0, 0, `invalid request body: malformed JSON`)
+}
+
+func TestNewBindError(t *testing.T) {
+	type user struct {
+		Age int `validate:"gte=18"`
+	}
+	validation := validator.New().Struct(user{Age: 3})
+
+	_, numErr := strconv.Atoi("x")
+	_, convErr := Int64("x")
+	var typeErr error = &json.UnmarshalTypeError{Value: "string", Type: reflect.TypeOf(int32(0)), Field: "age"}
+
+	tests := []struct {
+		name   string
+		source string
+		field  string
+		err    error
+		status int
+		want   string // 期望的 Field
+		msg    string
+	}{
+		{"convert", "path parameter", "author_id", convErr, 400, "author_id", `invalid path parameter "author_id": want int64`},
+		{"wrapped convert", "header", "X-Page", fmt.Errorf("page: %w", convErr), 400, "X-Page", `invalid header "X-Page": want int64`},
+		{"strconv", "query parameter", "page", numErr, 400, "page", `invalid query parameter "page": want integer`},
+		{"json type", sourceBody, "", typeErr, 400, "age", `invalid request body "age": want int32`},
+		{"json syntax", sourceBody, "", &json.SyntaxError{Offset: 3}, 400, "", `invalid request body: malformed JSON`},
+		{"EOF", sourceBody, "", io.EOF, 400, "", `invalid request body: unexpected end of input`},
+		{"unexpected EOF", sourceBody, "", io.ErrUnexpectedEOF, 400, "", `invalid request body: unexpected end of input`},
+		{"validation", sourceBody, "", validation, 400, "user.Age", `invalid request body "user.Age": fails "gte=18" validation`},
+		{"max bytes", sourceBody, "", &http.MaxBytesError{Limit: 1024}, 413, "", `request body exceeds 1024 bytes`},
+		{"multipart too large", sourceBody, "", multipart.ErrMessageTooLarge, 413, "", `request body is too large`},
+		{"unclassified", "cookie", "session", errors.New("secret detail"), 400, "session", `invalid cookie "session"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			be := NewBindError(tt.source, tt.field, tt.err)
+			checkBindError(t, be, tt.status, tt.want, 0, 0, tt.msg)
+			// ValidationErrors 是切片，不能用 errors.Is 比较
+			if be.Source != tt.source || fmt.Sprint(errors.Unwrap(be)) != fmt.Sprint(tt.err) {
+				t.Errorf("source %q, unwrap %v", be.Source, be.Err)
+			}
+		})
+	}
+
+	// 已经是 BindError 时原样返回
+	be := &BindError{Status: 415, Message: "x"}
+	if got := NewBindError("header", "h", fmt.Errorf("wrap: %w", be)); got != be {
+		t.Errorf("NewBindError did not return the wrapped BindError: %+v", got)
+	}
+}
+
+func TestAsBindError(t *testing.T) {
+	_, numErr := strconv.ParseBool("x")
+
+	tests := []struct {
+		name   string
+		err    error
+		ok     bool
+		status int
+		msg    string
+	}{
+		{"strconv", numErr, true, 400, "invalid parameter: want bool"},
+		{"json syntax", &json.SyntaxError{Offset: 1}, true, 400, "invalid request body: malformed JSON"},
+		{"empty body", io.EOF, true, 400, "invalid request body: unexpected end of input"},
+		{"max bytes", fmt.Errorf("bind: %w", &http.MaxBytesError{Limit: 10}), true, 413, "request body exceeds 10 bytes"},
+		{"multipart too large", multipart.ErrMessageTooLarge, true, 413, "request body is too large"},
+		// 只有未包装的 io.EOF 视为空请求体
+		{"wrapped EOF", fmt.Errorf("read: %w", io.EOF), false, 0, ""},
+		{"unclassified", errors.New("db down"), false, 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			be, ok := asBindError(tt.err)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && (be.Status != tt.status || be.Message != tt.msg) {
+				t.Errorf("got %d %q, want %d %q", be.Status, be.Message, tt.status, tt.msg)
+			}
+		})
+	}
+}
+
+func TestBindErrorCode(t *testing.T) {
+	for status, want := range map[int]string{400: "InvalidArgument", 413: "ResourceExhausted", 415: "InvalidArgument"} {
+		be := &BindError{Status: status}
+		if got := be.GetCode(); got != want {
+			t.Errorf("GetCode() for %d = %q, want %q", status, got, want)
+		}
+		if _, code, _ := ErrorStatus(be); code != want {
+			t.Errorf("ErrorStatus code for %d = %q, want %q", status, code, want)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package runtime
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// BodyDecoder 基于 protojson 的请求体解码器
+//
+// 与 encoding/json 不同，protojson 按 proto3 JSON 映射处理 oneof、字符串形式的 int64、
+// 枚举名称、json_name 以及 Timestamp/Struct/Any 等 well-known 类型
+type BodyDecoder struct {
+	// UnmarshalOptions protojson 解码选项，DiscardUnknown 控制是否忽略未知字段
+	UnmarshalOptions protojson.UnmarshalOptions
+	// MultipartMemory 解析 multipart 请求时保存在内存中的最大字节数，超过的文件部分写入临时文件，0 表示 DefaultMultipartMemory。
+	// 方法设置了 (protogin.method).max_body_size 时不超过该限制，见 LimitBody
+	MultipartMemory int64
+}
+
+// DefaultBodyDecoder 默认解码器，忽略未知字段
+var DefaultBodyDecoder = &BodyDecoder{
+	UnmarshalOptions: protojson.UnmarshalOptions{DiscardUnknown: true},
+}
+
+// Decode 把整个请求体解码到请求消息中（body: "*"），空请求体视为空消息。
+// multipart/form-data 请求按表单解码，见 decodeMultipart
+func (d *BodyDecoder) Decode(r *http.Request, msg proto.Message) error {
+	if isMultipart(r) {
+		return decodeMultipart(r, msg, d.MultipartMemory)
+	}
+
+	data, err := readBody(r)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	if err := checkContentType(r); err != nil {
+		return err
+	}
+
+	if err := d.UnmarshalOptions.Unmarshal(data, msg); err != nil {
+		return invalidBody("", err, data)
+	}
+	return nil
+}
+
+// DecodeField 把请求体解码到请求消息的顶层字段中（body: "field"），不影响其他字段
+func (d *BodyDecoder) DecodeField(r *http.Request, msg proto.Message, field string) error {
+	m := msg.ProtoReflect()
+	fd := m.Descriptor().Fields().ByName(protoreflect.Name(field))
+	if fd == nil {
+		return status.Errorf(codes.Internal, "body field %q not found in %s", field, m.Descriptor().FullName())
+	}
+
+	data, err := readBody(r)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	// multipart/form-data 只支持 body: "*"
+	if err := checkContentType(r); err != nil {
+		return err
+	}
+
+	// 把请求体包装为 {"field": body} 解码到临时消息中，
+	// 这样标量、repeated、map 和消息字段都按 protojson 规则处理
+	var buf bytes.Buffer
+	buf.Grow(len(data) + len(field) + 5)
+	fmt.Fprintf(&buf, "{%q:", field)
+	prefix := buf.Len()
+	buf.Write(data)
+	buf.WriteByte('}')
+
+	tmp := m.New()
+	if err := d.UnmarshalOptions.Unmarshal(buf.Bytes(), tmp.Interface()); err != nil {
+		be, reason := parseBodyError("", err, nil)
+		switch be.Field {
+		case "", fd.JSONName(), string(fd.Name()):
+			// 语法错误或字段本身的值错误，如 body: "page_size" 的请求体不是数字
+			be.Field = field
+		default:
+			be.Field = joinField(field, be.Field)
+		}
+		// 错误位置按原始请求体计算
+		if be.Line == 1 {
+			be.Column = max(be.Column-prefix, 1)
+		}
+		be.Message = bindMessage(describe(sourceBody, be.Field), be.Line, be.Column, reason)
+		return be
+	}
+	if tmp.Has(fd) {
+		m.Set(fd, tmp.Get(fd))
+	} else {
+		m.Clear(fd)
+	}
+	return nil
+}
+
+// DecodeJSON 使用 encoding/json 解码请求体（插件参数 json=std），空请求体不做处理。
+// v 为请求消息时 multipart/form-data 请求按表单解码
+func DecodeJSON(r *http.Request, v any) error {
+	if msg, ok := v.(proto.Message); ok && isMultipart(r) {
+		return decodeMultipart(r, msg, 0)
+	}
+
+	data, err := readBody(r)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	if err := checkContentType(r); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return invalidBody("", err, data)
+	}
+	return nil
+}
+
+// LimitBody 用 http.MaxBytesReader 把请求体限制为 n 字节，超过时解码返回 413 BindError，
+// multipart 请求保存在内存中的字节数同样不超过 n。由生成代码按 (protogin.method).max_body_size 调用
+func LimitBody(w http.ResponseWriter, r *http.Request, n int64) {
+	r.Body = &limitedBody{ReadCloser: http.MaxBytesReader(w, r.Body, n), limit: n}
+}
+
+// limitedBody 记录 LimitBody 限制的请求体
+type limitedBody struct {
+	io.ReadCloser
+	limit int64
+}
+
+func readBody(r *http.Request) ([]byte, error) {
+	data, err := readRawBody(r)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimSpace(data), nil
+}
+
+// readRawBody 读取完整的请求体，超过 http.MaxBytesReader 的限制时返回 413 BindError
+func readRawBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		if be, ok := bodyTooLarge(err); ok {
+			return nil, be
+		}
+		return nil, NewBindError(sourceBody, "", fmt.Errorf("read request body: %w", err))
+	}
+	return data, nil
+}
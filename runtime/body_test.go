@@ -0,0 +1,134 @@
+package runtime
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/JarrettGuo/protogin/internal/testproto"
+)
+
+func TestDecodeField(t *testing.T) {
+	md := testproto.Message(t, queryProto, "Query")
+	const initial = `{"name":"keep","inner":{"id":"old","tags":["x"]},"ids":["9"]}`
+
+	tests := []struct {
+		name  string
+		field string
+		body  string
+		want  string // 期望的消息，protojson 格式
+	}{
+		{"message field", "inner", `{"id":"a","child":{"id":"b"}}`, `{"name":"keep","inner":{"id":"a","child":{"id":"b"}},"ids":["9"]}`},
+		{"message field replaced", "inner", `{"child":{"id":"b"}}`, `{"name":"keep","inner":{"child":{"id":"b"}},"ids":["9"]}`},
+		{"null clears the field", "inner", `null`, `{"name":"keep","ids":["9"]}`},
+		{"repeated field", "ids", ` ["1", 2] `, `{"name":"keep","inner":{"id":"old","tags":["x"]},"ids":["1","2"]}`},
+		{"map field", "labels", `{"a":1}`, `{"name":"keep","inner":{"id":"old","tags":["x"]},"ids":["9"],"labels":{"a":1}}`},
+		{"scalar field", "name", `"new"`, `{"name":"new","inner":{"id":"old","tags":["x"]},"ids":["9"]}`},
+		{"wrapper field", "limit", `5`, `{"name":"keep","inner":{"id":"old","tags":["x"]},"ids":["9"],"limit":"5"}`},
+		{"empty body", "inner", ``, initial},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := dynamicpb.NewMessage(md)
+			if err := protojson.Unmarshal([]byte(initial), msg); err != nil {
+				t.Fatal(err)
+			}
+			r := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", "application/json")
+			if err := DefaultBodyDecoder.DecodeField(r, msg, tt.field); err != nil {
+				t.Fatalf("DecodeField: %v", err)
+			}
+
+			want := dynamicpb.NewMessage(md)
+			if err := protojson.Unmarshal([]byte(tt.want), want); err != nil {
+				t.Fatal(err)
+			}
+			if !proto.Equal(msg, want) {
+				t.Errorf("got %v, want %v", msg, want)
+			}
+		})
+	}
+}
+
+func TestDecodeFieldErrors(t *testing.T) {
+	md := testproto.Message(t, queryProto, "Query")
+	strict := &BodyDecoder{}
+
+	tests := []struct {
+		name        string
+		decoder     *BodyDecoder
+		field       string
+		contentType string
+		body        string
+		status      int
+		errField    string
+		line        int
+		column      int
+		msg         string
+	}{
+		{
+			name: "type error in a nested field", field: "inner", body: `{"id":5}`,
+			status: 400, errField: "inner.id", line: 1, column: 7,
+			msg: `invalid request body "inner.id" (line 1:7): want string`,
+		},
+		{
+			name: "type error on a later line", field: "inner", body: "{\n  \"tags\": [\"a\"],\n  \"id\": true\n}",
+			status: 400, errField: "inner.id", line: 3, column: 9,
+			msg: `invalid request body "inner.id" (line 3:9): want string`,
+		},
+		{
+			name: "type error in a scalar body", field: "page_size", body: `"x"`,
+			status: 400, errField: "page_size", line: 1, column: 1,
+			msg: `invalid request body "page_size" (line 1:1): want int32`,
+		},
+		{
+			name: "unknown nested field", decoder: strict, field: "inner", body: `{"id":"a","foo":1}`,
+			status: 400, errField: "inner.foo", line: 1, column: 11,
+			msg: `invalid request body "inner.foo" (line 1:11): unknown field`,
+		},
+		{
+			name: "syntax error", field: "inner", body: `{"id" "a"}`,
+			status: 400, errField: "inner", line: 1, column: 7,
+			msg: `invalid request body "inner" (line 1:7): malformed JSON`,
+		},
+		{
+			name: "unsupported Content-Type", field: "inner", contentType: "text/plain", body: `{}`,
+			status: 415, msg: `unsupported Content-Type "text/plain", want application/json`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := tt.decoder
+			if d == nil {
+				d = DefaultBodyDecoder
+			}
+			r := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+			err := d.DecodeField(r, dynamicpb.NewMessage(md), tt.field)
+			var be *BindError
+			if !errors.As(err, &be) {
+				t.Fatalf("error %v (%T), want *BindError", err, err)
+			}
+			checkBindError(t, be, tt.status, tt.errField, tt.line, tt.column, tt.msg)
+		})
+	}
+}
+
+func TestDecodeFieldUnknownField(t *testing.T) {
+	md := testproto.Message(t, queryProto, "Query")
+	r := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{}`))
+	err := DefaultBodyDecoder.DecodeField(r, dynamicpb.NewMessage(md), "user")
+	if s, _ := status.FromError(err); s.Code() != codes.Internal || s.Message() != `body field "user" not found in protogin.test.Query` {
+		t.Errorf("%v, want Internal", err)
+	}
+}
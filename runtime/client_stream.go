@@ -0,0 +1,259 @@
+package runtime
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// ContentTypeFramedJSON 客户端流式请求体的长度前缀编码：每条 protojson 消息前有 5 字节前缀，
+// 1 字节标志（必须为 0）和 4 字节大端消息长度，与 gRPC 的消息帧一致。其他 Content-Type 按 NDJSON 读取
+const ContentTypeFramedJSON = "application/x-protojson-stream"
+
+// DefaultMaxMessageSize 客户端流式请求中单条消息的默认最大字节数，与 gRPC 服务端的默认值一致
+const DefaultMaxMessageSize = 4 << 20
+
+// ClientStream 把 gin 请求体适配为 grpc.ServerStream，每次 RecvMsg 读取并解码一条消息
+//
+// 服务实现通过 SendMsg（SendAndClose）设置唯一的响应，由生成的处理函数按普通请求写入。
+// context 被取消（客户端断开或超时）后，阻塞中的读取立即返回
+type ClientStream struct {
+	c    *gin.Context
+	ctx  context.Context
+	opts *ServerOptions
+
+	body   *bufio.Reader
+	framed bool
+	n      int // 已读取的消息数
+	stop   func() bool
+
+	header   metadata.MD
+	response proto.Message
+}
+
+func newClientStream(c *gin.Context, ctx context.Context, opts *ServerOptions) *ClientStream {
+	s := &ClientStream{
+		c:      c,
+		ctx:    ctx,
+		opts:   opts,
+		header: metadata.MD{},
+		stop:   func() bool { return false },
+	}
+	if mt, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type")); mt == ContentTypeFramedJSON {
+		s.framed = true
+	}
+	if c.Request.Body != nil && c.Request.Body != http.NoBody {
+		s.body = bufio.NewReader(c.Request.Body)
+		// 通过读超时打断阻塞中的 Read，底层连接不支持时只能等待 Read 自然返回
+		rc := http.NewResponseController(c.Writer)
+		s.stop = context.AfterFunc(ctx, func() {
+			_ = rc.SetReadDeadline(time.Now())
+		})
+	}
+	return s
+}
+
+// Context 返回请求的 context
+func (s *ClientStream) Context() context.Context {
+	return s.ctx
+}
+
+// SetHeader 设置响应头，与响应一起写入
+func (s *ClientStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+// SendHeader HTTP 响应头只能与响应一起写入，与 SetHeader 相同
+func (s *ClientStream) SendHeader(md metadata.MD) error {
+	return s.SetHeader(md)
+}
+
+// SetTrailer HTTP 响应不支持 trailer，忽略
+func (s *ClientStream) SetTrailer(metadata.MD) {}
+
+// SendMsg 设置响应消息，只能调用一次
+func (s *ClientStream) SendMsg(m any) error {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "stream message %T is not a proto message", m)
+	}
+	if s.response != nil {
+		return status.Error(codes.Internal, "response already sent")
+	}
+	s.response = msg
+	return nil
+}
+
+// RecvMsg 读取并解码下一条消息，请求体结束时返回 io.EOF
+func (s *ClientStream) RecvMsg(m any) error {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "stream message %T is not a proto message", m)
+	}
+	if err := s.ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	if s.body == nil {
+		return io.EOF
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+	if s.framed {
+		data, err = s.readFrame()
+	} else {
+		data, err = s.readLine()
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := s.opts.BodyDecoder.UnmarshalOptions.Unmarshal(data, msg); err != nil {
+		return invalidBody(s.field(), err, data)
+	}
+	s.n++
+	return nil
+}
+
+// readLine 读取下一个非空的 NDJSON 行
+func (s *ClientStream) readLine() ([]byte, error) {
+	var line []byte
+	for {
+		chunk, err := s.body.ReadSlice('\n')
+		// 多出的 1 字节为换行符
+		if len(line)+len(chunk) > s.opts.MaxMessageSize+1 {
+			return nil, s.tooLarge()
+		}
+		line = append(line, chunk...)
+		switch {
+		case err == bufio.ErrBufferFull:
+			continue
+		case err != nil && err != io.EOF:
+			return nil, s.readError(err)
+		}
+
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			return trimmed, nil
+		}
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		line = line[:0]
+	}
+}
+
+// readFrame 读取下一条长度前缀消息
+func (s *ClientStream) readFrame() ([]byte, error) {
+	var prefix [5]byte
+	if _, err := io.ReadFull(s.body, prefix[:]); err != nil {
+		return nil, s.readError(err)
+	}
+	if prefix[0] != 0 {
+		return nil, s.invalid(fmt.Sprintf("message %d: unsupported frame flag %#x", s.n, prefix[0]), nil)
+	}
+	size := binary.BigEndian.Uint32(prefix[1:])
+	if uint64(size) > uint64(s.opts.MaxMessageSize) {
+		return nil, s.tooLarge()
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(s.body, data); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, s.readError(err)
+	}
+	return data, nil
+}
+
+// readError 把读取请求体的错误转换为 gRPC 错误或 BindError，正常结束时返回 io.EOF
+func (s *ClientStream) readError(err error) error {
+	if be, ok := bodyTooLarge(err); ok {
+		return be
+	}
+	switch {
+	case err == io.EOF:
+		return io.EOF
+	case s.ctx.Err() != nil:
+		return status.FromContextError(s.ctx.Err()).Err()
+	case err == io.ErrUnexpectedEOF:
+		return s.invalid(fmt.Sprintf("message %d: truncated frame", s.n), err)
+	}
+	return s.invalid("failed to read request body", err)
+}
+
+func (s *ClientStream) tooLarge() error {
+	return &BindError{
+		Status:  http.StatusRequestEntityTooLarge,
+		Source:  sourceBody,
+		Field:   s.field(),
+		Message: fmt.Sprintf("message %d exceeds %d bytes", s.n, s.opts.MaxMessageSize),
+	}
+}
+
+func (s *ClientStream) invalid(message string, err error) error {
+	return &BindError{Status: http.StatusBadRequest, Source: sourceBody, Field: s.field(), Message: message, Err: err}
+}
+
+// field 当前读取的消息在请求体中的位置，用于错误信息
+func (s *ClientStream) field() string {
+	return fmt.Sprintf("body[%d]", s.n)
+}
+
+// finish 停止监听 context，把 metadata 写入响应头
+func (s *ClientStream) finish() {
+	s.stop()
+	setMetadataHeader(s.c.Writer.Header(), s.header)
+}
+
+// ClientStreamingServer 实现 grpc.ClientStreamingServer[Req, Res]，用于客户端流式方法
+type ClientStreamingServer[Req, Res any] struct {
+	*ClientStream
+}
+
+// NewClientStreamingServer 由生成的处理函数调用，ctx 为请求的 context（可能带有超时）
+func NewClientStreamingServer[Req, Res any](c *gin.Context, ctx context.Context, opts *ServerOptions) *ClientStreamingServer[Req, Res] {
+	return &ClientStreamingServer[Req, Res]{ClientStream: newClientStream(c, ctx, opts)}
+}
+
+// Recv 读取下一条请求消息，请求体结束时返回 io.EOF
+func (s *ClientStreamingServer[Req, Res]) Recv() (*Req, error) {
+	m := new(Req)
+	if err := s.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// SendAndClose 设置响应消息
+func (s *ClientStreamingServer[Req, Res]) SendAndClose(m *Res) error {
+	return s.SendMsg(m)
+}
+
+// Finish 结束流，err 为服务实现的返回值，返回需要写入的响应
+func (s *ClientStreamingServer[Req, Res]) Finish(err error) (*Res, error) {
+	s.finish()
+	if err != nil {
+		return nil, err
+	}
+	res, ok := any(s.response).(*Res)
+	if !ok || res == nil {
+		return nil, status.Error(codes.Internal, "method returned without calling SendAndClose")
+	}
+	return res, nil
+}
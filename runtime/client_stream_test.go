@@ -0,0 +1,215 @@
+package runtime
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type testClientStreamServer = ClientStreamingServer[wrapperspb.StringValue, wrapperspb.StringValue]
+
+// newClientStreamHandler 与生成代码一样以 handler 为客户端流式方法实现的处理函数，timeout 为 0 表示不限制
+func newClientStreamHandler(handler func(*testClientStreamServer) error, timeout time.Duration, opts ...ServerOption) http.Handler {
+	gin.SetMode(gin.TestMode)
+	o := NewServerOptions(append([]ServerOption{WithResponseWriter(RawWriter{})}, opts...)...)
+
+	r := gin.New()
+	r.POST("/import", func(c *gin.Context) {
+		ctx := c.Request.Context()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		stream := NewClientStreamingServer[wrapperspb.StringValue, wrapperspb.StringValue](c, ctx, o)
+		out, err := stream.Finish(handler(stream))
+		if err != nil {
+			o.ErrorHandler(c, err)
+			return
+		}
+		data, err := o.Marshaler.Marshal(out)
+		if err != nil {
+			o.ErrorHandler(c, err)
+			return
+		}
+		o.ResponseWriter.WriteResponse(c, http.StatusOK, data)
+	})
+	return r
+}
+
+// join 读取全部消息，用逗号连接后作为响应；读取失败时把错误写入 errp
+func join(errp *error) func(*testClientStreamServer) error {
+	return func(s *testClientStreamServer) error {
+		var values []string
+		for {
+			m, err := s.Recv()
+			if err == io.EOF {
+				return s.SendAndClose(wrapperspb.String(strings.Join(values, ",")))
+			}
+			if err != nil {
+				*errp = err
+				return err
+			}
+			values = append(values, m.GetValue())
+		}
+	}
+}
+
+// frame 返回长度前缀编码的消息
+func frame(flag byte, msg string) string {
+	var prefix [5]byte
+	prefix[0] = flag
+	binary.BigEndian.PutUint32(prefix[1:], uint32(len(msg)))
+	return string(prefix[:]) + msg
+}
+
+func TestClientStream(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        string // 成功时的响应
+		status      int    // 失败时的状态码和 BindError
+		field       string
+		message     string
+	}{
+		{name: "NDJSON", contentType: "application/x-ndjson", body: "\"a\"\n\n  \n\"b\"\r\n\"c\"", want: `"a,b,c"`},
+		{name: "NDJSON without Content-Type", body: "\"a\"\n", want: `"a"`},
+		{name: "empty body", contentType: "application/x-ndjson", body: "", want: `""`},
+		{name: "framed", contentType: ContentTypeFramedJSON, body: frame(0, `"a"`) + frame(0, `"b\nc"`), want: `"a,b\nc"`},
+		{name: "framed with parameters", contentType: ContentTypeFramedJSON + "; charset=utf-8", body: frame(0, `"a"`), want: `"a"`},
+		{
+			name: "invalid NDJSON line", contentType: "application/x-ndjson", body: "\"a\"\n\"b\n",
+			status: 400, field: "body[1]", message: `invalid request body "body[1]": unexpected end of input`,
+		},
+		{
+			name: "truncated frame", contentType: ContentTypeFramedJSON, body: frame(0, `"a"`) + frame(0, `"bcdef"`)[:7],
+			status: 400, field: "body[1]", message: "message 1: truncated frame",
+		},
+		{
+			name: "truncated prefix", contentType: ContentTypeFramedJSON, body: frame(0, `"a"`) + "\x00\x00",
+			status: 400, field: "body[1]", message: "message 1: truncated frame",
+		},
+		{
+			name: "compressed frame", contentType: ContentTypeFramedJSON, body: frame(1, `"a"`),
+			status: 400, field: "body[0]", message: "message 0: unsupported frame flag 0x1",
+		},
+		{
+			name: "NDJSON line too large", contentType: "application/x-ndjson", body: "\"a\"\n\"" + strings.Repeat("x", 16) + "\"\n",
+			status: 413, field: "body[1]", message: "message 1 exceeds 8 bytes",
+		},
+		{
+			name: "frame too large", contentType: ContentTypeFramedJSON, body: frame(0, `"`+strings.Repeat("x", 16)+`"`),
+			status: 413, field: "body[0]", message: "message 0 exceeds 8 bytes",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var recvErr error
+			h := newClientStreamHandler(join(&recvErr), 0, WithMaxMessageSize(8))
+
+			r := httptest.NewRequest(http.MethodPost, "/import", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if tt.status == 0 {
+				if w.Code != http.StatusOK || w.Body.String() != tt.want {
+					t.Errorf("%d %s, want 200 %s", w.Code, w.Body, tt.want)
+				}
+				return
+			}
+			var be *BindError
+			if !errors.As(recvErr, &be) {
+				t.Fatalf("Recv error %v (%T), want *BindError", recvErr, recvErr)
+			}
+			if w.Code != tt.status || be.Status != tt.status || be.Field != tt.field || tt.message != "" && be.Message != tt.message {
+				t.Errorf("%d %d %q %q, want %d %q %q", w.Code, be.Status, be.Field, be.Message, tt.status, tt.field, tt.message)
+			}
+		})
+	}
+}
+
+// TestClientStreamBodyLimit 请求体超过 LimitBody（max_body_size）时返回 413
+func TestClientStreamBodyLimit(t *testing.T) {
+	var recvErr error
+	h := newClientStreamHandler(join(&recvErr), 0)
+	limited := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		LimitBody(w, r, 8)
+		h.ServeHTTP(w, r)
+	})
+
+	w := httptest.NewRecorder()
+	limited.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/import", strings.NewReader("\"a\"\n\"bcdefgh\"\n")))
+	var be *BindError
+	if w.Code != http.StatusRequestEntityTooLarge || !errors.As(recvErr, &be) || be.Status != http.StatusRequestEntityTooLarge {
+		t.Errorf("%d %v, want 413 BindError", w.Code, recvErr)
+	}
+}
+
+func TestClientStreamWithoutSendAndClose(t *testing.T) {
+	h := newClientStreamHandler(func(s *testClientStreamServer) error {
+		_, err := s.Recv()
+		return err
+	}, 0)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/import", strings.NewReader("\"a\"\n")))
+	if body := decodeBody(t, w.Body); w.Code != http.StatusInternalServerError || body["code"] != "Internal" {
+		t.Errorf("%d %v, want 500 Internal", w.Code, body)
+	}
+}
+
+func TestClientStreamSendAndCloseTwice(t *testing.T) {
+	var second error
+	h := newClientStreamHandler(func(s *testClientStreamServer) error {
+		_ = s.SendAndClose(wrapperspb.String("first"))
+		second = s.SendAndClose(wrapperspb.String("second"))
+		return nil
+	}, 0)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/import", strings.NewReader("")))
+	if w.Code != http.StatusOK || w.Body.String() != `"first"` || status.Code(second) != codes.Internal {
+		t.Errorf("%d %s, second SendAndClose %v", w.Code, w.Body, second)
+	}
+}
+
+// TestClientStreamTimeout 方法超时后通过读超时打断阻塞中的 Recv
+func TestClientStreamTimeout(t *testing.T) {
+	var recvErr error
+	srv := httptest.NewServer(newClientStreamHandler(join(&recvErr), 100*time.Millisecond))
+	defer srv.Close()
+
+	// 发送一条消息后不再发送也不结束请求体
+	body, pw := io.Pipe()
+	defer pw.Close()
+	go io.WriteString(pw, "\"a\"\n")
+
+	start := time.Now()
+	resp, err := http.Post(srv.URL+"/import", "application/x-ndjson", body)
+	if err != nil {
+		t.Fatalf("POST: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Recv returned after %v", elapsed)
+	}
+	if resp.StatusCode != http.StatusGatewayTimeout || status.Code(recvErr) != codes.DeadlineExceeded {
+		t.Errorf("%d %v, want 504 DeadlineExceeded", resp.StatusCode, recvErr)
+	}
+}
@@ -0,0 +1,217 @@
+package runtime
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// ConvertError 参数值无法转换为字段类型，错误信息只包含期望的类型（如 want int64），
+// 原始错误（如 *strconv.NumError）可以通过 errors.Unwrap 取得
+type ConvertError struct {
+	Want string
+	Err  error
+}
+
+func (e *ConvertError) Error() string {
+	return "want " + e.Want
+}
+
+func (e *ConvertError) Unwrap() error {
+	return e.Err
+}
+
+// 以下函数用于把路径参数的字符串值转换为请求字段的类型，生成代码按字段类型选择调用，转换失败时返回 *ConvertError
+
+// String 原样返回字符串，用于 proto3 optional 字段
+func String(val string) (string, error) {
+	return val, nil
+}
+
+// Bool 转换 bool 字段
+func Bool(val string) (bool, error) {
+	v, err := strconv.ParseBool(val)
+	if err != nil {
+		return false, &ConvertError{Want: "bool", Err: err}
+	}
+	return v, nil
+}
+
+// Int32 转换 int32/sint32/sfixed32 字段
+func Int32(val string) (int32, error) {
+	i, err := strconv.ParseInt(val, 10, 32)
+	if err != nil {
+		return 0, &ConvertError{Want: "int32", Err: err}
+	}
+	return int32(i), nil
+}
+
+// Int64 转换 int64/sint64/sfixed64 字段
+func Int64(val string) (int64, error) {
+	i, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return 0, &ConvertError{Want: "int64", Err: err}
+	}
+	return i, nil
+}
+
+// Uint32 转换 uint32/fixed32 字段
+func Uint32(val string) (uint32, error) {
+	i, err := strconv.ParseUint(val, 10, 32)
+	if err != nil {
+		return 0, &ConvertError{Want: "uint32", Err: err}
+	}
+	return uint32(i), nil
+}
+
+// Uint64 转换 uint64/fixed64 字段
+func Uint64(val string) (uint64, error) {
+	i, err := strconv.ParseUint(val, 10, 64)
+	if err != nil {
+		return 0, &ConvertError{Want: "uint64", Err: err}
+	}
+	return i, nil
+}
+
+// Float32 转换 float 字段
+func Float32(val string) (float32, error) {
+	f, err := strconv.ParseFloat(val, 32)
+	if err != nil {
+		return 0, &ConvertError{Want: "float", Err: err}
+	}
+	return float32(f), nil
+}
+
+// Float64 转换 double 字段
+func Float64(val string) (float64, error) {
+	f, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return 0, &ConvertError{Want: "double", Err: err}
+	}
+	return f, nil
+}
+
+// Bytes 转换 bytes 字段，接受标准和 URL 安全的 base64 编码（可省略填充）
+func Bytes(val string) ([]byte, error) {
+	val = strings.TrimRight(val, "=")
+	if b, err := base64.RawStdEncoding.DecodeString(val); err == nil {
+		return b, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(val)
+	if err != nil {
+		return nil, &ConvertError{Want: "base64 bytes", Err: err}
+	}
+	return b, nil
+}
+
+// Enum 转换枚举字段，接受枚举值名称或数字
+func Enum(val string, values map[string]int32) (int32, error) {
+	if v, ok := values[val]; ok {
+		return v, nil
+	}
+	i, err := strconv.ParseInt(val, 10, 32)
+	if err != nil {
+		return 0, &ConvertError{Want: "enum name or number", Err: err}
+	}
+	return int32(i), nil
+}
+
+// Timestamp 转换 google.protobuf.Timestamp 字段，格式为 RFC 3339
+func Timestamp(val string) (*timestamppb.Timestamp, error) {
+	t, err := time.Parse(time.RFC3339Nano, val)
+	if err != nil {
+		return nil, &ConvertError{Want: "RFC 3339 timestamp", Err: err}
+	}
+	return timestamppb.New(t), nil
+}
+
+// Duration 转换 google.protobuf.Duration 字段，格式如 1.5s
+func Duration(val string) (*durationpb.Duration, error) {
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		return nil, &ConvertError{Want: "duration such as 1.5s", Err: err}
+	}
+	return durationpb.New(d), nil
+}
+
+// StringValue 转换 google.protobuf.StringValue 字段
+func StringValue(val string) (*wrapperspb.StringValue, error) {
+	return wrapperspb.String(val), nil
+}
+
+// BoolValue 转换 google.protobuf.BoolValue 字段
+func BoolValue(val string) (*wrapperspb.BoolValue, error) {
+	v, err := Bool(val)
+	if err != nil {
+		return nil, err
+	}
+	return wrapperspb.Bool(v), nil
+}
+
+// Int32Value 转换 google.protobuf.Int32Value 字段
+func Int32Value(val string) (*wrapperspb.Int32Value, error) {
+	v, err := Int32(val)
+	if err != nil {
+		return nil, err
+	}
+	return wrapperspb.Int32(v), nil
+}
+
+// Int64Value 转换 google.protobuf.Int64Value 字段
+func Int64Value(val string) (*wrapperspb.Int64Value, error) {
+	v, err := Int64(val)
+	if err != nil {
+		return nil, err
+	}
+	return wrapperspb.Int64(v), nil
+}
+
+// UInt32Value 转换 google.protobuf.UInt32Value 字段
+func UInt32Value(val string) (*wrapperspb.UInt32Value, error) {
+	v, err := Uint32(val)
+	if err != nil {
+		return nil, err
+	}
+	return wrapperspb.UInt32(v), nil
+}
+
+// UInt64Value 转换 google.protobuf.UInt64Value 字段
+func UInt64Value(val string) (*wrapperspb.UInt64Value, error) {
+	v, err := Uint64(val)
+	if err != nil {
+		return nil, err
+	}
+	return wrapperspb.UInt64(v), nil
+}
+
+// FloatValue 转换 google.protobuf.FloatValue 字段
+func FloatValue(val string) (*wrapperspb.FloatValue, error) {
+	v, err := Float32(val)
+	if err != nil {
+		return nil, err
+	}
+	return wrapperspb.Float(v), nil
+}
+
+// DoubleValue 转换 google.protobuf.DoubleValue 字段
+func DoubleValue(val string) (*wrapperspb.DoubleValue, error) {
+	v, err := Float64(val)
+	if err != nil {
+		return nil, err
+	}
+	return wrapperspb.Double(v), nil
+}
+
+// BytesValue 转换 google.protobuf.BytesValue 字段
+func BytesValue(val string) (*wrapperspb.BytesValue, error) {
+	v, err := Bytes(val)
+	if err != nil {
+		return nil, err
+	}
+	return wrapperspb.Bytes(v), nil
+}
@@ -0,0 +1,159 @@
+package runtime
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// convertCase 一次转换的输入和期望的结果，want 为 *ConvertError 的 Want，为空表示转换成功
+type convertCase struct {
+	in   string
+	out  any
+	want string
+}
+
+func TestConvert(t *testing.T) {
+	enum := map[string]int32{"ACTIVE": 1}
+	convert := map[string]func(string) (any, error){
+		"Bool":    func(s string) (any, error) { return Bool(s) },
+		"Int32":   func(s string) (any, error) { return Int32(s) },
+		"Int64":   func(s string) (any, error) { return Int64(s) },
+		"Uint32":  func(s string) (any, error) { return Uint32(s) },
+		"Uint64":  func(s string) (any, error) { return Uint64(s) },
+		"Float32": func(s string) (any, error) { return Float32(s) },
+		"Float64": func(s string) (any, error) { return Float64(s) },
+		"Bytes":   func(s string) (any, error) { return Bytes(s) },
+		"String":  func(s string) (any, error) { return String(s) },
+		"Enum":    func(s string) (any, error) { return Enum(s, enum) },
+	}
+
+	tests := map[string][]convertCase{
+		"Bool": {
+			{"true", true, ""}, {"0", false, ""}, {"yes", false, "bool"},
+		},
+		"Int32": {
+			{"-42", int32(-42), ""}, {"2147483648", int32(0), "int32"}, {"1.5", int32(0), "int32"}, {"", int32(0), "int32"},
+		},
+		"Int64": {
+			{"9223372036854775807", int64(9223372036854775807), ""}, {"x", int64(0), "int64"},
+		},
+		"Uint32": {
+			{"4294967295", uint32(4294967295), ""}, {"-1", uint32(0), "uint32"},
+		},
+		"Uint64": {
+			{"18446744073709551615", uint64(18446744073709551615), ""}, {"-1", uint64(0), "uint64"},
+		},
+		"Float32": {
+			{"1.5", float32(1.5), ""}, {"x", float32(0), "float"},
+		},
+		"Float64": {
+			{"-2.25", -2.25, ""}, {"1e400", float64(0), "double"},
+		},
+		"Bytes": {
+			{"aGk=", "hi", ""}, {"aGk", "hi", ""}, {"-_8", "\xfb\xff", ""}, {"+/8=", "\xfb\xff", ""}, {"***", "", "base64 bytes"},
+		},
+		"String": {
+			{"a:b/c", "a:b/c", ""},
+		},
+		"Enum": {
+			{"ACTIVE", int32(1), ""}, {"2", int32(2), ""}, {"BOGUS", int32(0), "enum name or number"},
+		},
+	}
+	for name, cases := range tests {
+		for _, tt := range cases {
+			got, err := convert[name](tt.in)
+			if b, ok := got.([]byte); ok {
+				got = string(b)
+			}
+			if tt.want == "" {
+				if err != nil || got != tt.out {
+					t.Errorf("%s(%q) = %v, %v; want %v", name, tt.in, got, err, tt.out)
+				}
+				continue
+			}
+			checkConvertError(t, name, tt.in, err, tt.want)
+		}
+	}
+}
+
+func checkConvertError(t *testing.T, name, in string, err error, want string) {
+	t.Helper()
+	var ce *ConvertError
+	if !errors.As(err, &ce) {
+		t.Errorf("%s(%q) error %v (%T), want *ConvertError", name, in, err, err)
+		return
+	}
+	if ce.Want != want || ce.Error() != "want "+want || errors.Unwrap(ce) == nil {
+		t.Errorf("%s(%q) error %q (Want %q), want %q", name, in, ce.Error(), ce.Want, "want "+want)
+	}
+}
+
+func TestConvertWellKnown(t *testing.T) {
+	convert := map[string]func(string) (proto.Message, error){
+		"Timestamp":   func(s string) (proto.Message, error) { return Timestamp(s) },
+		"Duration":    func(s string) (proto.Message, error) { return Duration(s) },
+		"StringValue": func(s string) (proto.Message, error) { return StringValue(s) },
+		"BoolValue":   func(s string) (proto.Message, error) { return BoolValue(s) },
+		"Int32Value":  func(s string) (proto.Message, error) { return Int32Value(s) },
+		"Int64Value":  func(s string) (proto.Message, error) { return Int64Value(s) },
+		"UInt32Value": func(s string) (proto.Message, error) { return UInt32Value(s) },
+		"UInt64Value": func(s string) (proto.Message, error) { return UInt64Value(s) },
+		"FloatValue":  func(s string) (proto.Message, error) { return FloatValue(s) },
+		"DoubleValue": func(s string) (proto.Message, error) { return DoubleValue(s) },
+		"BytesValue":  func(s string) (proto.Message, error) { return BytesValue(s) },
+	}
+
+	tests := []struct {
+		name string
+		in   string
+		out  proto.Message
+		want string
+	}{
+		{"Timestamp", "2024-01-02T03:04:05.5+08:00", timestamppb.New(time.Date(2024, 1, 1, 19, 4, 5, 5e8, time.UTC)), ""},
+		{"Timestamp", "2024-01-02", nil, "RFC 3339 timestamp"},
+		{"Duration", "1m30s", durationpb.New(90 * time.Second), ""},
+		{"Duration", "90", nil, "duration such as 1.5s"},
+		{"StringValue", "", wrapperspb.String(""), ""},
+		{"BoolValue", "false", wrapperspb.Bool(false), ""},
+		{"BoolValue", "nope", nil, "bool"},
+		{"Int32Value", "7", wrapperspb.Int32(7), ""},
+		{"Int32Value", "x", nil, "int32"},
+		{"Int64Value", "-7", wrapperspb.Int64(-7), ""},
+		{"Int64Value", "x", nil, "int64"},
+		{"UInt32Value", "7", wrapperspb.UInt32(7), ""},
+		{"UInt32Value", "-7", nil, "uint32"},
+		{"UInt64Value", "7", wrapperspb.UInt64(7), ""},
+		{"UInt64Value", "x", nil, "uint64"},
+		{"FloatValue", "0.5", wrapperspb.Float(0.5), ""},
+		{"FloatValue", "x", nil, "float"},
+		{"DoubleValue", "0.25", wrapperspb.Double(0.25), ""},
+		{"DoubleValue", "x", nil, "double"},
+		{"BytesValue", "aGk", wrapperspb.Bytes([]byte("hi")), ""},
+		{"BytesValue", "!", nil, "base64 bytes"},
+	}
+	for _, tt := range tests {
+		got, err := convert[tt.name](tt.in)
+		if tt.want == "" {
+			if err != nil || !proto.Equal(got, tt.out) {
+				t.Errorf("%s(%q) = %v, %v; want %v", tt.name, tt.in, got, err, tt.out)
+			}
+			continue
+		}
+		checkConvertError(t, tt.name, tt.in, err, tt.want)
+	}
+}
+
+func TestConvertErrorUnwrap(t *testing.T) {
+	_, err := Int32("x")
+	var numErr *strconv.NumError
+	if !errors.As(err, &numErr) || numErr.Func != "ParseInt" {
+		t.Errorf("ConvertError does not unwrap to *strconv.NumError: %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package runtime
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+
+	perrors "github.com/JarrettGuo/protogin/errors"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// ErrorDetails 返回错误携带的 google.rpc.Status 详情，如 errdetails.BadRequest、RetryInfo、ErrorInfo 和 LocalizedMessage
+//
+// 详情来自 gRPC 状态或 protogin/errors 的业务错误；只有错误码而没有 domain 和 metadata 的 ErrorInfo 不会返回，
+// 错误码已经在响应中。无法解析的详情为 *anypb.Any
+func ErrorDetails(err error) []proto.Message {
+	if be, ok := asBindError(err); ok {
+		err = be
+	}
+	e, ok := perrors.FromError(err)
+	if !ok {
+		return nil
+	}
+	var details []proto.Message
+	if e.Domain != "" || len(e.Metadata) > 0 || e.Reason != "" && e.Reason != e.Code {
+		reason := e.Reason
+		if reason == "" {
+			reason = e.Code
+		}
+		details = append(details, &errdetails.ErrorInfo{Reason: reason, Domain: e.Domain, Metadata: e.Metadata})
+	}
+	return append(details, e.Details()...)
+}
+
+// writeErrorDetails 把错误详情写入错误响应：details 为带有 @type 的 protojson，BadRequest 的字段错误写入 fields，
+// RetryInfo 写入 Retry-After 响应头
+func writeErrorDetails(c *gin.Context, err error, body gin.H) {
+	items, fields := errorDetails(c, err)
+	if len(items) > 0 {
+		body["details"] = items
+	}
+	if len(fields) > 0 {
+		body["fields"] = fields
+	}
+}
+
+// errorDetails 返回编码后的错误详情和 BadRequest 中的字段错误，RetryInfo 写入 Retry-After 响应头
+func errorDetails(c *gin.Context, err error) (items []json.RawMessage, fields []gin.H) {
+	for _, d := range ErrorDetails(err) {
+		items = append(items, marshalDetail(d))
+		switch d := d.(type) {
+		case *errdetails.BadRequest:
+			for _, v := range d.GetFieldViolations() {
+				f := gin.H{"field": v.GetField(), "description": v.GetDescription()}
+				if v.GetReason() != "" {
+					f["reason"] = v.GetReason()
+				}
+				fields = append(fields, f)
+			}
+		case *errdetails.RetryInfo:
+			if delay := d.GetRetryDelay(); delay != nil {
+				seconds := int64(math.Ceil(delay.AsDuration().Seconds()))
+				c.Header("Retry-After", strconv.FormatInt(max(seconds, 0), 10))
+			}
+		}
+	}
+	return items, fields
+}
+
+// marshalDetail 把详情编码为带有 @type 的 protojson，类型未注册时输出 @type 和 base64 编码的 value
+func marshalDetail(d proto.Message) json.RawMessage {
+	a, ok := d.(*anypb.Any)
+	if !ok {
+		var err error
+		if a, err = anypb.New(d); err != nil {
+			return unknownDetail(&anypb.Any{TypeUrl: "type.googleapis.com/" + string(d.ProtoReflect().Descriptor().FullName())})
+		}
+	}
+	data, err := protojson.Marshal(a)
+	if err != nil {
+		return unknownDetail(a)
+	}
+	return data
+}
+
+func unknownDetail(a *anypb.Any) json.RawMessage {
+	data, _ := json.Marshal(map[string]any{"@type": a.GetTypeUrl(), "value": a.GetValue()})
+	return data
+}
@@ -0,0 +1,78 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	perrors "github.com/JarrettGuo/protogin/errors"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// BizError 业务错误接口（用于单体应用），实现见 protogin/errors 包
+type BizError = perrors.BizError
+
+// ErrorHandler 定义错误处理器类型
+type ErrorHandler func(c *gin.Context, err error)
+
+// DefaultErrorHandler 默认错误处理器（支持单体和分布式），按统一响应格式输出
+func DefaultErrorHandler(c *gin.Context, err error) {
+	if err == nil {
+		return
+	}
+	EnvelopeWriter{}.WriteError(c, err)
+}
+
+// ErrorStatus 返回错误对应的 HTTP 状态码、错误码和错误信息
+//
+// 业务错误使用自身的错误码，protogin 自己产生的错误码都是 gRPC 状态码的名称（如 InvalidArgument、NotFound、Internal）
+func ErrorStatus(err error) (httpStatus int, code string, message string) {
+	// 1. 优先检查是否是业务错误（单体应用场景）
+	var bizErr BizError
+	if errors.As(err, &bizErr) {
+		return bizErr.GetStatus(), bizErr.GetCode(), bizErr.Error()
+	}
+
+	// 2. context 被取消或超时（如 (protogin.method).timeout 到期），按 gRPC 的 Canceled 和 DeadlineExceeded 处理
+	// 只使用 context 错误本身的信息，不包含外层包装的文本
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		err = status.FromContextError(context.DeadlineExceeded).Err()
+	case errors.Is(err, context.Canceled):
+		err = status.FromContextError(context.Canceled).Err()
+	}
+
+	// 3. 检查是否是 gRPC 错误（分布式场景），带有 ErrorInfo 时还原后端的业务错误码和 HTTP 状态码
+	if s, ok := grpcStatus(err); ok {
+		if e := perrors.FromGRPCStatus(s); e != nil {
+			return e.Status, e.Code, e.Message
+		}
+		return HTTPStatusFromCode(s.Code()), s.Code().String(), s.Message()
+	}
+
+	// 4. 参数绑定错误（生成的处理函数返回 BindError，已在 1 中处理），如服务实现中 gin 绑定返回的错误
+	if be, ok := asBindError(err); ok {
+		return be.Status, be.GetCode(), be.Error()
+	}
+
+	// 5. 默认错误处理，未分类的错误可能包含内部信息（如数据库错误），不返回给客户端
+	return http.StatusInternalServerError, codes.Internal.String(), "internal error"
+}
+
+// grpcStatus 返回错误链中的 gRPC 状态。与 status.FromError 不同，被包装的状态保留自身的信息，
+// 不使用包含外层包装文本的 err.Error()
+func grpcStatus(err error) (*status.Status, bool) {
+	var gs interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &gs) {
+		return nil, false
+	}
+	s := gs.GRPCStatus()
+	return s, s != nil
+}
+
+// HTTPStatusFromCode 将 gRPC 状态码转换为 HTTP 状态码，与 errors.HTTPStatusFromCode 相同
+func HTTPStatusFromCode(code codes.Code) int {
+	return perrors.HTTPStatusFromCode(code)
+}
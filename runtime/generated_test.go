@@ -0,0 +1,164 @@
+package runtime_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	apiv1 "github.com/JarrettGuo/protogin/gen/api/v1"
+	"github.com/JarrettGuo/protogin/runtime"
+)
+
+// demoServer 记录生成的处理函数传给服务实现的 context 和请求
+type demoServer struct {
+	apiv1.UnimplementedDemoServiceServer
+
+	createErr error
+	deadline  time.Time
+	ban       *apiv1.BanUserRequest
+}
+
+func (s *demoServer) CreateUser(ctx context.Context, in *apiv1.CreateUserRequest) (*apiv1.CreateUserResponse, error) {
+	s.deadline, _ = ctx.Deadline()
+	if s.createErr != nil {
+		return nil, s.createErr
+	}
+	return &apiv1.CreateUserResponse{UserId: "1"}, nil
+}
+
+func (s *demoServer) ListUsers(ctx context.Context, in *apiv1.ListUsersRequest) (*apiv1.ListUsersResponse, error) {
+	if in.GetPage() < 0 {
+		return nil, context.Canceled
+	}
+	return &apiv1.ListUsersResponse{Users: []*apiv1.User{{UserId: "1", Name: "alice", Phone: "13800000000"}}, Total: 1}, nil
+}
+
+func (s *demoServer) BanUser(ctx context.Context, in *apiv1.BanUserRequest) (*apiv1.BanUserResponse, error) {
+	s.ban = in
+	return &apiv1.BanUserResponse{Success: true}, nil
+}
+
+// newDemoServer 用生成的代码注册 DemoService，返回服务实现和 gin engine
+func newDemoServer(t *testing.T) (*demoServer, *gin.Engine) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	srv := &demoServer{}
+	r := gin.New()
+	apiv1.RegisterDemoServiceServerHTTPServer(srv, r,
+		runtime.WithResponseWriter(runtime.RawWriter{}),
+		runtime.WithMiddleware("audit"),
+		runtime.WithAuthorizer(func(*gin.Context, []string) error { return nil }),
+	)
+	return srv, r
+}
+
+func do(r http.Handler, method, path, body string, header ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// TestGeneratedTimeout (protogin.method).timeout 设置传给服务实现的 context 的截止时间，超时错误返回 504
+func TestGeneratedTimeout(t *testing.T) {
+	srv, r := newDemoServer(t)
+
+	start := time.Now()
+	if w := do(r, http.MethodPost, "/api/v1/users", `{"name":"alice"}`); w.Code != http.StatusCreated {
+		t.Fatalf("%d %s, want 201", w.Code, w.Body)
+	}
+	if d := srv.deadline.Sub(start); d <= 4*time.Second || d > 5*time.Second+time.Second/2 {
+		t.Errorf("deadline in %v, want 5s", d)
+	}
+
+	srv.createErr = context.DeadlineExceeded
+	w := do(r, http.MethodPost, "/api/v1/users", `{"name":"alice"}`)
+	if w.Code != http.StatusGatewayTimeout || !strings.Contains(w.Body.String(), `"code":"DeadlineExceeded"`) {
+		t.Errorf("%d %s, want 504 DeadlineExceeded", w.Code, w.Body)
+	}
+}
+
+// TestGeneratedCacheControl (protogin.method).cache_ttl 只在成功响应中输出 Cache-Control，包括 additional_bindings
+func TestGeneratedCacheControl(t *testing.T) {
+	_, r := newDemoServer(t)
+
+	for _, path := range []string{"/api/v1/users", "/api/v1/legacy/users"} {
+		if w := do(r, http.MethodGet, path, ""); w.Code != http.StatusOK || w.Header().Get("Cache-Control") != "max-age=30" {
+			t.Errorf("GET %s = %d Cache-Control %q, want 200 max-age=30", path, w.Code, w.Header().Get("Cache-Control"))
+		}
+	}
+	if w := do(r, http.MethodGet, "/api/v1/users?page=-1", ""); w.Header().Get("Cache-Control") != "" {
+		t.Errorf("error response %d has Cache-Control %q", w.Code, w.Header().Get("Cache-Control"))
+	}
+	// 没有 cache_ttl 的方法不输出 Cache-Control
+	if w := do(r, http.MethodPost, "/api/v1/users", `{}`); w.Header().Get("Cache-Control") != "" {
+		t.Errorf("POST /api/v1/users has Cache-Control %q", w.Header().Get("Cache-Control"))
+	}
+}
+
+// TestGeneratedResponseBody additional_bindings 的 response_body: "users" 只返回用户数组
+func TestGeneratedResponseBody(t *testing.T) {
+	_, r := newDemoServer(t)
+
+	w := do(r, http.MethodGet, "/api/v1/legacy/users", "")
+	var users []map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &users); err != nil || len(users) != 1 {
+		t.Fatalf("%d %s: %v, want a user array", w.Code, w.Body, err)
+	}
+	if users[0]["user_id"] != "1" || users[0]["phone"] != "" {
+		t.Errorf("user %v, want user_id 1 with empty phone", users[0])
+	}
+}
+
+// TestGeneratedRedact 响应中的 (protogin.field).redact 字段被清除
+func TestGeneratedRedact(t *testing.T) {
+	_, r := newDemoServer(t)
+
+	w := do(r, http.MethodGet, "/api/v1/users", "")
+	var out struct {
+		Users []map[string]any `json:"users"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil || len(out.Users) != 1 {
+		t.Fatalf("%s: %v", w.Body, err)
+	}
+	if out.Users[0]["phone"] != "" || out.Users[0]["name"] != "alice" {
+		t.Errorf("user %v, want name with empty phone", out.Users[0])
+	}
+}
+
+// TestGeneratedHeaderBinding (protogin.field).header 字段只能从请求头设置，请求体中的值被清除
+func TestGeneratedHeaderBinding(t *testing.T) {
+	srv, r := newDemoServer(t)
+
+	do(r, http.MethodPost, "/api/v1/users/7:ban", `{"reason":"spam","operator":"mallory"}`, "X-Operator", "admin")
+	if srv.ban.GetOperator() != "admin" || srv.ban.GetReason() != "spam" || srv.ban.GetUserId() != "7" {
+		t.Errorf("request %v, want operator from header", srv.ban)
+	}
+
+	do(r, http.MethodPost, "/api/v1/users/7:ban", `{"reason":"spam","operator":"mallory"}`)
+	if srv.ban.GetOperator() != "" {
+		t.Errorf("operator %q from body, want empty", srv.ban.GetOperator())
+	}
+}
+
+// TestGeneratedMissingMiddleware 方法声明的中间件标签没有通过 WithMiddleware 提供时注册 panic
+func TestGeneratedMissingMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	defer func() {
+		want := `protogin: middleware "audit" is not registered, use runtime.WithMiddleware`
+		if r := recover(); r != want {
+			t.Errorf("panic = %v, want %s", r, want)
+		}
+	}()
+	apiv1.RegisterDemoServiceServerHTTPServer(&demoServer{}, gin.New(), runtime.WithAuthorizer(func(*gin.Context, []string) error { return nil }))
+}
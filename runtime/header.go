@@ -0,0 +1,34 @@
+package runtime
+
+import (
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Header 读取请求头，用于 (protogin.field).header 绑定，请求头不存在时 ok 为 false
+func Header(c *gin.Context, key string) (value string, ok bool) {
+	values := c.Request.Header.Values(key)
+	if len(values) == 0 {
+		return "", false
+	}
+	return values[0], true
+}
+
+// Cookie 读取 cookie，用于 (protogin.field).cookie 绑定，cookie 不存在时 ok 为 false
+func Cookie(c *gin.Context, name string) (value string, ok bool) {
+	value, err := c.Cookie(name)
+	return value, err == nil
+}
+
+// ClearFields 清除请求消息中从请求头和 cookie 绑定的顶层字段，生成的处理函数在解码请求体之后调用，
+// 避免请求头或 cookie 不存在时客户端通过请求体（如 body: "*" 或 multipart 表单）设置这些字段
+func ClearFields(msg proto.Message, names ...string) {
+	m := msg.ProtoReflect()
+	fields := m.Descriptor().Fields()
+	for _, name := range names {
+		if fd := fields.ByName(protoreflect.Name(name)); fd != nil {
+			m.Clear(fd)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package runtime
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestHeaderAndCookie(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Request.Header.Add("X-Operator", "alice")
+	c.Request.Header.Add("X-Operator", "bob")
+	c.Request.Header.Set("X-Empty", "")
+	c.Request.AddCookie(&http.Cookie{Name: "sid", Value: "s1"})
+
+	tests := []struct {
+		name   string
+		lookup func(*gin.Context, string) (string, bool)
+		key    string
+		value  string
+		ok     bool
+	}{
+		{"first header value", Header, "x-operator", "alice", true},
+		{"empty header", Header, "X-Empty", "", true},
+		{"missing header", Header, "X-Missing", "", false},
+		{"cookie", Cookie, "sid", "s1", true},
+		{"missing cookie", Cookie, "other", "", false},
+	}
+	for _, tt := range tests {
+		if value, ok := tt.lookup(c, tt.key); value != tt.value || ok != tt.ok {
+			t.Errorf("%s: %q %v, want %q %v", tt.name, value, ok, tt.value, tt.ok)
+		}
+	}
+}
+
+func TestClearFields(t *testing.T) {
+	account := accounts(t)
+	msg := account(`{"name": "alice", "operator": "mallory", "session": "forged", "key": {"id": "k1"}}`)
+	// 不存在的字段名被忽略
+	ClearFields(msg, "operator", "session", "missing")
+
+	want := account(`{"name": "alice", "key": {"id": "k1"}}`)
+	if !proto.Equal(msg, want) {
+		t.Errorf("ClearFields = %v, want %v", msg, want)
+	}
+}
@@ -0,0 +1,88 @@
+package runtime
+
+import (
+	"bytes"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/genproto/googleapis/api/httpbody"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/JarrettGuo/protogin/gen/protogin"
+)
+
+// HTTPBodyMessage 原样传输请求体和响应体的消息类型，插件也按它判断生成代码是否调用 DecodeHTTPBody 和 WriteHTTPBody
+const HTTPBodyMessage protoreflect.FullName = "google.api.HttpBody"
+
+// DecodeHTTPBody 把原始请求体和 Content-Type 写入 google.api.HttpBody，不做 JSON 解码。
+// field 为空时 msg 本身是 HttpBody（body: "*"），否则写入 msg 的 body 字段
+func DecodeHTTPBody(r *http.Request, msg proto.Message, field string) error {
+	m := msg.ProtoReflect()
+	if field != "" {
+		fd := m.Descriptor().Fields().ByName(protoreflect.Name(field))
+		if fd == nil || fd.Message() == nil {
+			return status.Errorf(codes.Internal, "body field %q not found in %s", field, m.Descriptor().FullName())
+		}
+		m = m.Mutable(fd).Message()
+	}
+	if m.Descriptor().FullName() != HTTPBodyMessage {
+		return status.Errorf(codes.Internal, "%s is not %s", m.Descriptor().FullName(), HTTPBodyMessage)
+	}
+
+	data, err := readRawBody(r)
+	if err != nil {
+		return err
+	}
+	fields := m.Descriptor().Fields()
+	m.Set(fields.ByName("content_type"), protoreflect.ValueOfString(r.Header.Get("Content-Type")))
+	m.Set(fields.ByName("data"), protoreflect.ValueOfBytes(data))
+	return nil
+}
+
+// WriteHTTPBody 按 content_type 原样写入 google.api.HttpBody 响应，不经过 ResponseWriter 和 JSON 编码
+//
+// 状态码为 200 时通过 http.ServeContent 支持 Range 请求（206）和 If-Range；
+// extensions 中的 protogin.ContentDisposition 输出为 Content-Disposition
+func WriteHTTPBody(c *gin.Context, code int, body *httpbody.HttpBody) {
+	setHTTPBodyHeader(c.Writer.Header(), body)
+	if code == http.StatusOK {
+		http.ServeContent(c.Writer, c.Request, "", time.Time{}, bytes.NewReader(body.GetData()))
+		return
+	}
+	c.Status(code)
+	_, _ = c.Writer.Write(body.GetData())
+}
+
+// setHTTPBodyHeader 设置 HttpBody 响应的 Content-Type 和 Content-Disposition
+func setHTTPBodyHeader(h http.Header, body *httpbody.HttpBody) {
+	if ct := body.GetContentType(); ct != "" {
+		h.Set("Content-Type", ct)
+	}
+	for _, ext := range body.GetExtensions() {
+		if cd := contentDisposition(ext); cd != "" {
+			h.Set("Content-Disposition", cd)
+		}
+	}
+}
+
+// contentDisposition 解析 extensions 中的 protogin.ContentDisposition，其他类型返回空
+func contentDisposition(ext *anypb.Any) string {
+	var cd protogin.ContentDisposition
+	if !ext.MessageIs(&cd) || ext.UnmarshalTo(&cd) != nil {
+		return ""
+	}
+	disposition := "attachment"
+	if cd.GetInline() {
+		disposition = "inline"
+	}
+	if cd.GetFilename() == "" {
+		return disposition
+	}
+	return mime.FormatMediaType(disposition, map[string]string{"filename": cd.GetFilename()})
+}
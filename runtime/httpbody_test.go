@@ -0,0 +1,234 @@
+package runtime
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/genproto/googleapis/api/httpbody"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/JarrettGuo/protogin/gen/protogin"
+	"github.com/JarrettGuo/protogin/internal/testproto"
+)
+
+// uploadBodyProto body 字段为 google.api.HttpBody 的请求消息
+//
+//	message UploadBody {
+//	  string name = 1;
+//	  google.api.HttpBody file = 2;
+//	  string note = 3;
+//	}
+const uploadBodyProto = `
+name: "httpbody_test.proto"
+package: "protogin.test"
+syntax: "proto3"
+dependency: ["google/api/httpbody.proto"]
+message_type {
+  name: "UploadBody"
+  field { name: "name" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING }
+  field { name: "file" number: 2 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.api.HttpBody" }
+  field { name: "note" number: 3 label: LABEL_OPTIONAL type: TYPE_STRING }
+}
+`
+
+// disposition 返回带有 protogin.ContentDisposition 扩展的 HttpBody
+func disposition(body *httpbody.HttpBody, cd *protogin.ContentDisposition) *httpbody.HttpBody {
+	ext, _ := anypb.New(cd)
+	body.Extensions = append(body.Extensions, ext)
+	return body
+}
+
+func TestWriteHTTPBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const data = "hello world"
+
+	tests := []struct {
+		name         string
+		code         int
+		rangeHeader  string
+		status       int
+		body         string
+		contentRange string
+	}{
+		{name: "full", code: http.StatusOK, status: http.StatusOK, body: data},
+		{name: "range", code: http.StatusOK, rangeHeader: "bytes=0-4", status: http.StatusPartialContent, body: "hello", contentRange: "bytes 0-4/11"},
+		{name: "suffix range", code: http.StatusOK, rangeHeader: "bytes=-5", status: http.StatusPartialContent, body: "world", contentRange: "bytes 6-10/11"},
+		{name: "unsatisfiable range", code: http.StatusOK, rangeHeader: "bytes=20-30", status: http.StatusRequestedRangeNotSatisfiable, contentRange: "bytes */11"},
+		{name: "invalid range", code: http.StatusOK, rangeHeader: "bytes=5-1", status: http.StatusRequestedRangeNotSatisfiable},
+		{name: "range ignored for other codes", code: http.StatusCreated, rangeHeader: "bytes=0-4", status: http.StatusCreated, body: data},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/download", nil)
+			if tt.rangeHeader != "" {
+				c.Request.Header.Set("Range", tt.rangeHeader)
+			}
+
+			WriteHTTPBody(c, tt.code, &httpbody.HttpBody{ContentType: "text/plain", Data: []byte(data)})
+			if w.Code != tt.status || tt.body != "" && w.Body.String() != tt.body {
+				t.Errorf("%d %q, want %d %q", w.Code, w.Body, tt.status, tt.body)
+			}
+			if got := w.Header().Get("Content-Range"); got != tt.contentRange {
+				t.Errorf("Content-Range %q, want %q", got, tt.contentRange)
+			}
+			if (tt.status == http.StatusOK || tt.status == http.StatusPartialContent) && w.Header().Get("Accept-Ranges") != "bytes" {
+				t.Errorf("Accept-Ranges %q, want bytes", w.Header().Get("Accept-Ranges"))
+			}
+			if tt.status != http.StatusRequestedRangeNotSatisfiable && w.Header().Get("Content-Type") != "text/plain" {
+				t.Errorf("Content-Type %q, want text/plain", w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestWriteHTTPBodyContentDisposition(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	other, _ := anypb.New(wrapperspb.String("users.csv"))
+
+	tests := []struct {
+		name string
+		body *httpbody.HttpBody
+		want string
+	}{
+		{"none", &httpbody.HttpBody{}, ""},
+		{"attachment", disposition(&httpbody.HttpBody{}, &protogin.ContentDisposition{Filename: "users.csv"}), `attachment; filename=users.csv`},
+		{"inline", disposition(&httpbody.HttpBody{}, &protogin.ContentDisposition{Filename: "a b.pdf", Inline: true}), `inline; filename="a b.pdf"`},
+		{"without filename", disposition(&httpbody.HttpBody{}, &protogin.ContentDisposition{}), "attachment"},
+		{"non-ASCII filename", disposition(&httpbody.HttpBody{}, &protogin.ContentDisposition{Filename: "用户.csv"}), `attachment; filename*=utf-8''%E7%94%A8%E6%88%B7.csv`},
+		{"other extensions ignored", &httpbody.HttpBody{Extensions: []*anypb.Any{other}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/download", nil)
+
+			WriteHTTPBody(c, http.StatusOK, tt.body)
+			if got := w.Header().Get("Content-Disposition"); got != tt.want {
+				t.Errorf("Content-Disposition %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeHTTPBody(t *testing.T) {
+	const data = "  a,b\n1,2\n\n"
+
+	// body: "*"，请求体原样写入，不去除空白
+	r := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(data))
+	r.Header.Set("Content-Type", "text/csv")
+	var body httpbody.HttpBody
+	if err := DecodeHTTPBody(r, &body, ""); err != nil {
+		t.Fatalf("DecodeHTTPBody: %v", err)
+	}
+	if string(body.GetData()) != data || body.GetContentType() != "text/csv" {
+		t.Errorf("%q %q", body.GetData(), body.GetContentType())
+	}
+
+	// body: "file"，其他字段保持不变
+	md := testproto.Message(t, uploadBodyProto, "UploadBody")
+	msg := dynamicpb.NewMessage(md)
+	msg.Set(md.Fields().ByName("name"), protoreflect.ValueOfString("report"))
+	r = httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(data))
+	r.Header.Set("Content-Type", "text/csv")
+	if err := DecodeHTTPBody(r, msg, "file"); err != nil {
+		t.Fatalf("DecodeHTTPBody: %v", err)
+	}
+	var file httpbody.HttpBody
+	b, _ := proto.Marshal(msg.Get(md.Fields().ByName("file")).Message().Interface())
+	if err := proto.Unmarshal(b, &file); err != nil {
+		t.Fatalf("unmarshal file: %v", err)
+	}
+	if string(file.GetData()) != data || file.GetContentType() != "text/csv" || msg.Get(md.Fields().ByName("name")).String() != "report" {
+		t.Errorf("%q %q %v", file.GetData(), file.GetContentType(), msg)
+	}
+}
+
+func TestDecodeHTTPBodyErrors(t *testing.T) {
+	md := testproto.Message(t, uploadBodyProto, "UploadBody")
+	tests := []struct {
+		name    string
+		msg     proto.Message
+		field   string
+		message string
+	}{
+		{"unknown field", dynamicpb.NewMessage(md), "data", `body field "data" not found in protogin.test.UploadBody`},
+		{"scalar field", dynamicpb.NewMessage(md), "note", `body field "note" not found in protogin.test.UploadBody`},
+		{"not HttpBody", &wrapperspb.StringValue{}, "", "google.protobuf.StringValue is not google.api.HttpBody"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader("data"))
+			err := DecodeHTTPBody(r, tt.msg, tt.field)
+			if s, _ := status.FromError(err); s.Code() != codes.Internal || s.Message() != tt.message {
+				t.Errorf("%v, want Internal %q", err, tt.message)
+			}
+		})
+	}
+}
+
+// TestDecodeHTTPBodyTooLarge 请求体超过 LimitBody（max_body_size）时返回 413
+func TestDecodeHTTPBodyTooLarge(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(strings.Repeat("x", 16)))
+	LimitBody(w, r, 8)
+
+	var be *BindError
+	if err := DecodeHTTPBody(r, &httpbody.HttpBody{}, ""); !errors.As(err, &be) || be.Status != http.StatusRequestEntityTooLarge {
+		t.Errorf("%v, want 413 BindError", err)
+	}
+}
+
+// TestServerStreamHTTPBody 流式返回 HttpBody 时原样写入各条消息的 data，开始发送后的错误通过 trailer 返回
+func TestServerStreamHTTPBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	o := NewServerOptions()
+
+	var streamErr error
+	r := gin.New()
+	r.GET("/export", func(c *gin.Context) {
+		s := NewServerStreamingServer[httpbody.HttpBody](c, c.Request.Context(), o)
+		first := disposition(&httpbody.HttpBody{ContentType: "text/csv", Data: []byte("id,name\n")}, &protogin.ContentDisposition{Filename: "users.csv"})
+		for _, chunk := range []*httpbody.HttpBody{first, {ContentType: "text/plain", Data: []byte("1,a\n")}, {Data: []byte("2,b\n")}} {
+			if err := s.Send(chunk); err != nil {
+				s.Finish(err)
+				return
+			}
+		}
+		s.Finish(streamErr)
+	})
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	for _, streamErr = range []error{nil, status.Error(codes.Aborted, "boom")} {
+		resp := get(t, srv.URL+"/export", "text/event-stream")
+		body, _ := io.ReadAll(resp.Body)
+		if string(body) != "id,name\n1,a\n2,b\n" {
+			t.Errorf("body %q", body)
+		}
+		if resp.Header.Get("Content-Type") != "text/csv" || resp.Header.Get("Content-Disposition") != "attachment; filename=users.csv" {
+			t.Errorf("Content-Type %q, Content-Disposition %q", resp.Header.Get("Content-Type"), resp.Header.Get("Content-Disposition"))
+		}
+
+		wantStatus, wantMessage := "", ""
+		if streamErr != nil {
+			wantStatus, wantMessage = "10", "boom"
+		}
+		if got := resp.Trailer.Get("Grpc-Status"); got != wantStatus || resp.Trailer.Get("Grpc-Message") != wantMessage {
+			t.Errorf("trailer %v, want Grpc-Status %q Grpc-Message %q", resp.Trailer, wantStatus, wantMessage)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package runtime
+
+import (
+	"encoding/json"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ResponseMarshaler 基于 protojson 的响应编码器
+//
+// MarshalOptions 中常用的选项：
+//   - EmitUnpopulated 输出零值字段
+//   - UseProtoNames 使用 proto 字段名（user_id）而不是 json_name（userId）
+//   - UseEnumNumbers 枚举输出为数字
+//   - Resolver 解析 google.protobuf.Any 中的消息类型
+type ResponseMarshaler struct {
+	MarshalOptions protojson.MarshalOptions
+}
+
+// DefaultResponseMarshaler 默认编码器，使用 proto 字段名并输出零值字段
+var DefaultResponseMarshaler = &ResponseMarshaler{
+	MarshalOptions: protojson.MarshalOptions{
+		UseProtoNames:   true,
+		EmitUnpopulated: true,
+	},
+}
+
+// Marshal 编码整个响应消息，返回值可以直接嵌入响应信封
+func (m *ResponseMarshaler) Marshal(msg proto.Message) (json.RawMessage, error) {
+	data, err := m.MarshalOptions.Marshal(msg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "marshal response: %v", err)
+	}
+	return data, nil
+}
+
+// MarshalField 只编码响应消息中的顶层字段（response_body: "field"）
+func (m *ResponseMarshaler) MarshalField(msg proto.Message, field string) (json.RawMessage, error) {
+	r := msg.ProtoReflect()
+	fd := r.Descriptor().Fields().ByName(protoreflect.Name(field))
+	if fd == nil {
+		return nil, status.Errorf(codes.Internal, "response field %q not found in %s", field, r.Descriptor().FullName())
+	}
+
+	// 编码只包含该字段的消息再取出字段值，保证字段值按 protojson 规则编码
+	tmp := r.New()
+	if r.IsValid() && r.Has(fd) {
+		tmp.Set(fd, r.Get(fd))
+	}
+	key := fd.JSONName()
+	if m.MarshalOptions.UseProtoNames {
+		key = string(fd.Name())
+	}
+
+	v, err := marshalKey(m.MarshalOptions, tmp.Interface(), key)
+	if err != nil || v != nil {
+		return v, err
+	}
+	// 字段未设置时按零值输出，如空数组
+	opts := m.MarshalOptions
+	opts.EmitUnpopulated = true
+	if v, err = marshalKey(opts, tmp.Interface(), key); err != nil || v != nil {
+		return v, err
+	}
+	return json.RawMessage("null"), nil
+}
+
+// marshalKey 编码消息并取出指定键的值，键不存在时返回 nil
+func marshalKey(opts protojson.MarshalOptions, msg proto.Message, key string) (json.RawMessage, error) {
+	data, err := opts.Marshal(msg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "marshal response: %v", err)
+	}
+
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, status.Errorf(codes.Internal, "marshal response: %v", err)
+	}
+	return obj[key], nil
+}
@@ -0,0 +1,73 @@
+package runtime
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/JarrettGuo/protogin/internal/testproto"
+)
+
+func TestMarshalField(t *testing.T) {
+	md := testproto.Message(t, queryProto, "Query")
+	jsonNames := &ResponseMarshaler{}
+
+	tests := []struct {
+		name      string
+		marshaler *ResponseMarshaler
+		msg       string // 响应消息，protojson 格式
+		field     string
+		want      string
+	}{
+		{name: "repeated field", msg: `{"ids":["1","2"],"name":"a"}`, field: "ids", want: `["1","2"]`},
+		{name: "unset repeated field", msg: `{"name":"a"}`, field: "ids", want: `[]`},
+		{name: "unset repeated field without EmitUnpopulated", marshaler: jsonNames, msg: `{}`, field: "ids", want: `[]`},
+		{name: "repeated message field", msg: `{"aliases":["a","b"]}`, field: "aliases", want: `["a","b"]`},
+		{name: "message field", msg: `{"inner":{"id":"a","tags":["x"]}}`, field: "inner", want: `{"id":"a","child":null,"tags":["x"]}`},
+		{name: "unset message field", msg: `{"name":"a"}`, field: "inner", want: `null`},
+		{name: "map field", msg: `{"labels":{"a":1}}`, field: "labels", want: `{"a":1}`},
+		{name: "unset map field", msg: `{}`, field: "labels", want: `{}`},
+		{name: "scalar field", msg: `{"pageSize":10}`, field: "page_size", want: `10`},
+		{name: "unset scalar field", msg: `{}`, field: "name", want: `""`},
+		{name: "json_name", marshaler: jsonNames, msg: `{"label":"x"}`, field: "display", want: `"x"`},
+		{name: "well-known type", msg: `{"limit":"5","ttl":"1.5s"}`, field: "ttl", want: `"1.500s"`},
+		{name: "unset oneof field", msg: `{}`, field: "email", want: `null`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.marshaler
+			if m == nil {
+				m = DefaultResponseMarshaler
+			}
+			msg := dynamicpb.NewMessage(md)
+			if err := protojson.Unmarshal([]byte(tt.msg), msg); err != nil {
+				t.Fatal(err)
+			}
+			data, err := m.MarshalField(msg, tt.field)
+			if err != nil {
+				t.Fatalf("MarshalField: %v", err)
+			}
+			// protojson 的输出带有随机空白
+			var got bytes.Buffer
+			if err := json.Compact(&got, data); err != nil {
+				t.Fatalf("%s: %v", data, err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("got %s, want %s", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestMarshalFieldUnknownField(t *testing.T) {
+	md := testproto.Message(t, queryProto, "Query")
+	_, err := DefaultResponseMarshaler.MarshalField(dynamicpb.NewMessage(md), "users")
+	if s, _ := status.FromError(err); s.Code() != codes.Internal || s.Message() != `response field "users" not found in protogin.test.Query` {
+		t.Errorf("%v, want Internal", err)
+	}
+}
@@ -0,0 +1,303 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/JarrettGuo/protogin/gen/protogin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// DefaultMultipartMemory 解析 multipart 请求时文件部分保存在内存中的最大字节数，超过的文件部分写入临时文件
+//
+// 写入 protogin.File 字段的大文件保留在临时文件中（File.path），bytes 字段的文件总是完整读入内存
+const DefaultMultipartMemory = 1 << 20
+
+const (
+	// maxMultipartParts multipart 请求最多包含的部分数，与 net/http 相同
+	maxMultipartParts = 1000
+	// maxMultipartValueMemory 普通字段最多占用的内存，与 net/http 相同
+	maxMultipartValueMemory = 10 << 20
+)
+
+// fileMessage 上传文件的标准消息，见 protogin/file.proto
+const fileMessage protoreflect.FullName = "protogin.File"
+
+// isMultipart 请求体是否为 multipart/form-data
+func isMultipart(r *http.Request) bool {
+	mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return mt == "multipart/form-data"
+}
+
+// uploadedFile multipart 请求中的一个文件部分，内容在 data 或临时文件 path 中
+type uploadedFile struct {
+	filename    string
+	contentType string
+	size        int64
+	data        []byte
+	path        string
+}
+
+// multipartForm 解析后的 multipart 表单
+type multipartForm struct {
+	value map[string][]string
+	file  map[string][]*uploadedFile
+}
+
+// removeAll 删除表单的临时文件
+func (f *multipartForm) removeAll() {
+	for _, files := range f.file {
+		for _, file := range files {
+			if file.path != "" {
+				os.Remove(file.path)
+			}
+		}
+	}
+}
+
+// decodeMultipart 把 multipart/form-data 请求体解码到请求消息中
+//
+// 普通字段按 query 参数的规则解析（字段名、嵌套消息、repeated、map 和 well-known 类型），
+// 文件部分写入 bytes 字段或 protogin.File 消息字段，同名的多个文件写入 repeated 字段。
+// 超过内存上限的文件部分写入临时文件，见 readMultipart 和 multipartMemory
+func decodeMultipart(r *http.Request, msg proto.Message, maxMemory int64) error {
+	form, err := readMultipart(r, multipartMemory(r, maxMemory))
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		if be, ok := bodyTooLarge(err); ok {
+			return be
+		}
+		return &BindError{Status: http.StatusBadRequest, Source: sourceBody, Message: "invalid multipart body", Err: err}
+	}
+
+	d := &queryDecoder{
+		source: "form field",
+		seen:   make(map[string]string),
+	}
+	m := msg.ProtoReflect()
+	for _, key := range sortedKeys(form.value) {
+		if err := d.populate(m, key, form.value[key]); err != nil {
+			form.removeAll()
+			return d.bindError(key, err)
+		}
+	}
+	for _, key := range sortedKeys(form.file) {
+		if err := d.populateFiles(m, key, form.file[key]); err != nil {
+			form.removeAll()
+			if _, ok := status.FromError(err); ok {
+				return err
+			}
+			return d.bindError(key, err)
+		}
+	}
+	return nil
+}
+
+// multipartMemory 解析 multipart 请求时文件部分保存在内存中的最大字节数：
+// maxMemory 为 0 时使用 DefaultMultipartMemory，请求体经 LimitBody 限制时不超过该限制
+func multipartMemory(r *http.Request, maxMemory int64) int64 {
+	if maxMemory <= 0 {
+		maxMemory = DefaultMultipartMemory
+	}
+	if body, ok := r.Body.(*limitedBody); ok {
+		maxMemory = min(maxMemory, body.limit)
+	}
+	return maxMemory
+}
+
+// readMultipart 逐个读取 multipart 请求的部分，文件部分共用 maxMemory 字节的内存，
+// 超过时每个文件写入单独的临时文件，在请求的 context 结束（请求处理完毕或客户端断开）后删除
+func readMultipart(r *http.Request, maxMemory int64) (_ *multipartForm, err error) {
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+	form := &multipartForm{
+		value: make(map[string][]string),
+		file:  make(map[string][]*uploadedFile),
+	}
+	defer func() {
+		if err != nil {
+			form.removeAll()
+		}
+	}()
+
+	valueMemory := int64(maxMultipartValueMemory)
+	for parts := 0; ; parts++ {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			return form, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if parts == maxMultipartParts {
+			return nil, multipart.ErrMessageTooLarge
+		}
+		name := p.FormName()
+		if name == "" {
+			continue
+		}
+
+		if p.FileName() == "" {
+			var b strings.Builder
+			n, err := io.Copy(&b, io.LimitReader(p, valueMemory+1))
+			if err != nil {
+				return nil, err
+			}
+			if valueMemory -= n; valueMemory < 0 {
+				return nil, multipart.ErrMessageTooLarge
+			}
+			form.value[name] = append(form.value[name], b.String())
+			continue
+		}
+
+		file := &uploadedFile{filename: p.FileName(), contentType: p.Header.Get("Content-Type")}
+		form.file[name] = append(form.file[name], file)
+		var b bytes.Buffer
+		n, err := io.CopyN(&b, p, maxMemory+1)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if n <= maxMemory {
+			maxMemory -= n
+			file.data, file.size = b.Bytes(), n
+			continue
+		}
+		if err := saveTempFile(r.Context(), file, io.MultiReader(&b, p)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// saveTempFile 把文件部分写入临时文件，context 结束后删除
+func saveTempFile(ctx context.Context, file *uploadedFile, r io.Reader) error {
+	tmp, err := os.CreateTemp("", "protogin-upload-")
+	if err != nil {
+		return status.Errorf(codes.Internal, "save uploaded file %q: %v", file.filename, err)
+	}
+	file.path = tmp.Name()
+	context.AfterFunc(ctx, func() { os.Remove(tmp.Name()) })
+
+	file.size, err = io.Copy(tmp, r)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	// 写入临时文件失败是服务端的错误，读取请求体失败（如超过 max_body_size）交给调用方处理
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		return status.Errorf(codes.Internal, "save uploaded file %q: %v", file.filename, err)
+	}
+	return err
+}
+
+// populateFiles 把同名的文件部分写入 bytes 或 protogin.File 字段
+func (d *queryDecoder) populateFiles(m protoreflect.Message, key string, files []*uploadedFile) error {
+	fields, err := d.lookup(m.Descriptor(), key, key)
+	if err != nil {
+		return err
+	}
+	fd := fields[len(fields)-1]
+	isFile := fd.Message() != nil && fd.Message().FullName() == fileMessage
+	if fd.IsMap() || fd.Kind() != protoreflect.BytesKind && !isFile {
+		return fmt.Errorf("%s %q: field %q cannot hold a file", d.source, key, fd.Name())
+	}
+	if m, err = d.parent(m, fields, key); err != nil {
+		return err
+	}
+
+	if fd.IsList() {
+		list := m.Mutable(fd).List()
+		for _, file := range files {
+			v, err := fileValue(list.NewElement, fd, file)
+			if err != nil {
+				return err
+			}
+			list.Append(v)
+		}
+		return nil
+	}
+
+	if len(files) > 1 {
+		return fmt.Errorf("%s %q is not repeated but has %d files", d.source, key, len(files))
+	}
+	path := make([]string, len(fields))
+	for i, f := range fields {
+		path[i] = string(f.Name())
+	}
+	if err := d.markSeen(strings.Join(path, "."), key, nil); err != nil {
+		return err
+	}
+	if err := d.checkOneof(m, fd, key); err != nil {
+		return err
+	}
+	v, err := fileValue(func() protoreflect.Value { return m.NewField(fd) }, fd, files[0])
+	if err != nil {
+		return err
+	}
+	m.Set(fd, v)
+	return nil
+}
+
+// fileValue 返回文件部分的 bytes 或 protogin.File 值
+//
+// bytes 字段的文件完整读入内存；protogin.File 字段中写入临时文件的文件只记录路径，其余内容写入 data
+func fileValue(newValue func() protoreflect.Value, fd protoreflect.FieldDescriptor, file *uploadedFile) (protoreflect.Value, error) {
+	if fd.Kind() == protoreflect.BytesKind {
+		if file.path == "" {
+			return protoreflect.ValueOfBytes(file.data), nil
+		}
+		data, err := os.ReadFile(file.path)
+		if err != nil {
+			return protoreflect.Value{}, status.Errorf(codes.Internal, "read uploaded file %q: %v", file.filename, err)
+		}
+		os.Remove(file.path)
+		return protoreflect.ValueOfBytes(data), nil
+	}
+
+	v := newValue()
+	m := v.Message()
+	fields := m.Descriptor().Fields()
+	m.Set(fields.ByName("filename"), protoreflect.ValueOfString(file.filename))
+	m.Set(fields.ByName("content_type"), protoreflect.ValueOfString(file.contentType))
+	m.Set(fields.ByName("size"), protoreflect.ValueOfInt64(file.size))
+	if file.path != "" {
+		m.Set(fields.ByName("path"), protoreflect.ValueOfString(file.path))
+	} else {
+		m.Set(fields.ByName("data"), protoreflect.ValueOfBytes(file.data))
+	}
+	return v, nil
+}
+
+// OpenFile 打开上传的文件：保存在临时文件中时读取 path，否则读取 data。
+// 临时文件在请求结束后删除，需要保留的文件应在处理函数返回前复制到别处
+func OpenFile(f *protogin.File) (io.ReadCloser, error) {
+	if f.GetPath() != "" {
+		return os.Open(f.GetPath())
+	}
+	return io.NopCloser(bytes.NewReader(f.GetData())), nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
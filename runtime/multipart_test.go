@@ -0,0 +1,219 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/JarrettGuo/protogin/gen/protogin"
+	"github.com/JarrettGuo/protogin/internal/testproto"
+)
+
+// uploadProto 上传测试消息
+//
+//	message Upload {
+//	  string description = 1;
+//	  protogin.File avatar = 2;
+//	  bytes raw = 3;
+//	  repeated protogin.File attachments = 4;
+//	}
+const uploadProto = `
+name: "upload_test.proto"
+package: "protogin.test"
+syntax: "proto3"
+dependency: "protogin/file.proto"
+message_type {
+  name: "Upload"
+  field { name: "description" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING }
+  field { name: "avatar" number: 2 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".protogin.File" }
+  field { name: "raw" number: 3 label: LABEL_OPTIONAL type: TYPE_BYTES }
+  field { name: "attachments" number: 4 label: LABEL_REPEATED type: TYPE_MESSAGE type_name: ".protogin.File" }
+}
+`
+
+// part multipart 请求中的一个部分，filename 为空时为普通字段
+type part struct {
+	name, filename, content string
+}
+
+func newMultipartRequest(t *testing.T, parts ...part) *http.Request {
+	t.Helper()
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for _, p := range parts {
+		var (
+			pw  io.Writer
+			err error
+		)
+		if p.filename == "" {
+			pw, err = w.CreateFormField(p.name)
+		} else {
+			pw, err = w.CreateFormFile(p.name, p.filename)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(pw, p.content)
+	}
+	w.Close()
+
+	r := httptest.NewRequest(http.MethodPost, "/upload", &buf)
+	r.Header.Set("Content-Type", w.FormDataContentType())
+	return r
+}
+
+func TestDecodeMultipart(t *testing.T) {
+	md := testproto.Message(t, uploadProto, "Upload")
+	big := strings.Repeat("x", 4096)
+
+	r := newMultipartRequest(t,
+		part{name: "description", content: "hello"},
+		part{name: "avatar", filename: "a.png", content: big},
+		part{name: "raw", filename: "raw.bin", content: big},
+		part{name: "attachments", filename: "1.txt", content: "one"},
+		part{name: "attachments", filename: "2.txt", content: "two"},
+	)
+	ctx, cancel := context.WithCancel(r.Context())
+	r = r.WithContext(ctx)
+	msg := dynamicpb.NewMessage(md)
+	// 内存上限小于文件大小，avatar 和 raw 写入临时文件
+	if err := (&BodyDecoder{MultipartMemory: 1024}).Decode(r, msg); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+
+	// protogin.File 字段只记录临时文件的路径
+	avatar := &protogin.File{}
+	data, _ := proto.Marshal(msg.Get(md.Fields().ByName("avatar")).Message().Interface())
+	if err := proto.Unmarshal(data, avatar); err != nil {
+		t.Fatal(err)
+	}
+	if avatar.Path == "" || avatar.Data != nil || avatar.Size != int64(len(big)) {
+		t.Fatalf("avatar = %v, want a %d-byte temp file without data", avatar, len(big))
+	}
+	f, err := OpenFile(avatar)
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	content, _ := io.ReadAll(f)
+	f.Close()
+	if string(content) != big {
+		t.Errorf("OpenFile read %d bytes, want %d", len(content), len(big))
+	}
+
+	// 小文件和 bytes 字段读入内存
+	want := dynamicpb.NewMessage(md)
+	text := `description: "hello"
+avatar { filename: "a.png" content_type: "application/octet-stream" size: 4096 path: "` + avatar.Path + `" }
+raw: "` + big + `"
+attachments { filename: "1.txt" content_type: "application/octet-stream" data: "one" size: 3 }
+attachments { filename: "2.txt" content_type: "application/octet-stream" data: "two" size: 3 }`
+	if err := prototext.Unmarshal([]byte(text), want); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(msg, want) {
+		t.Errorf("got %v\nwant %v", msg, want)
+	}
+
+	// 请求结束后删除临时文件
+	cancel()
+	for i := 0; i < 100; i++ {
+		if _, err := os.Stat(avatar.Path); errors.Is(err, fs.ErrNotExist) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("temp file %s was not removed after the request ended", avatar.Path)
+}
+
+func TestOpenFileInMemory(t *testing.T) {
+	f, err := OpenFile(&protogin.File{Data: []byte("one")})
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	defer f.Close()
+	if content, _ := io.ReadAll(f); string(content) != "one" {
+		t.Errorf("OpenFile read %q, want %q", content, "one")
+	}
+}
+
+// TestMultipartMemory 内存上限不超过 LimitBody（max_body_size）的限制
+func TestMultipartMemory(t *testing.T) {
+	tests := []struct {
+		maxMemory, limit, want int64 // limit 为 0 表示不调用 LimitBody
+	}{
+		{0, 0, DefaultMultipartMemory},
+		{1024, 0, 1024},
+		{0, 16 << 10, 16 << 10},
+		{1024, 16 << 10, 1024},
+		{0, 64 << 20, DefaultMultipartMemory},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(""))
+		if tt.limit > 0 {
+			LimitBody(httptest.NewRecorder(), r, tt.limit)
+		}
+		if got := multipartMemory(r, tt.maxMemory); got != tt.want {
+			t.Errorf("multipartMemory(%d) with limit %d = %d, want %d", tt.maxMemory, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeMultipartErrors(t *testing.T) {
+	md := testproto.Message(t, uploadProto, "Upload")
+
+	tests := []struct {
+		name  string
+		parts []part
+		field string
+		msg   string
+	}{
+		{"unknown file field", []part{{name: "nope", filename: "f", content: "x"}}, "nope", `unknown form field "nope"`},
+		{"file in a string field", []part{{name: "description", filename: "f", content: "x"}}, "description", `form field "description": field "description" cannot hold a file`},
+		{"several files in a single field", []part{{name: "avatar", filename: "a", content: "x"}, {name: "avatar", filename: "b", content: "y"}}, "avatar", `form field "avatar" is not repeated but has 2 files`},
+		{"unknown value field", []part{{name: "nope", content: "x"}}, "nope", `unknown form field "nope"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := DefaultBodyDecoder.Decode(newMultipartRequest(t, tt.parts...), dynamicpb.NewMessage(md))
+			var be *BindError
+			if !errors.As(err, &be) {
+				t.Fatalf("error %v (%T), want *BindError", err, err)
+			}
+			if be.Status != http.StatusBadRequest || be.Field != tt.field || be.Message != tt.msg {
+				t.Errorf("got %d %q %q, want 400 %q %q", be.Status, be.Field, be.Message, tt.field, tt.msg)
+			}
+		})
+	}
+}
+
+// TestDecodeMultipartTooLarge 与生成代码一样用 LimitBody 限制请求体（max_body_size），超过时返回 413
+func TestDecodeMultipartTooLarge(t *testing.T) {
+	md := testproto.Message(t, uploadProto, "Upload")
+
+	for _, memory := range []int64{0, 1024} {
+		r := newMultipartRequest(t, part{name: "avatar", filename: "a.png", content: strings.Repeat("x", 64<<10)})
+		LimitBody(httptest.NewRecorder(), r, 16<<10)
+
+		err := (&BodyDecoder{MultipartMemory: memory}).Decode(r, dynamicpb.NewMessage(md))
+		var be *BindError
+		if !errors.As(err, &be) || be.Status != http.StatusRequestEntityTooLarge {
+			t.Fatalf("memory %d: error %v, want 413 BindError", memory, err)
+		}
+		if status, code, _ := ErrorStatus(err); status != http.StatusRequestEntityTooLarge || code != "ResourceExhausted" {
+			t.Errorf("memory %d: ErrorStatus = %d %q", memory, status, code)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package runtime
+
+import (
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NegotiateErrorHandler 按请求的 Accept 选择错误格式
+//
+// formats 为媒体类型到错误处理器的映射，如 {"application/problem+json": ProblemWriter{}.WriteError}。
+// 某个媒体类型在 Accept 中的 q 值高于 application/json 时使用对应的处理器，否则（包括没有 Accept）使用 fallback，
+// 因此不发送 Accept 的已有客户端不受影响
+func NegotiateErrorHandler(fallback ErrorHandler, formats map[string]ErrorHandler) ErrorHandler {
+	types := make([]string, 0, len(formats))
+	for t := range formats {
+		types = append(types, t)
+	}
+	// 保证 q 值相同时的选择稳定
+	sort.Strings(types)
+
+	return func(c *gin.Context, err error) {
+		c.Writer.Header().Add("Vary", "Accept")
+
+		accept := parseAccept(c.GetHeader("Accept"))
+		h, best := fallback, quality(accept, "application/json")
+		for _, t := range types {
+			if q := quality(accept, t); q > best {
+				h, best = formats[t], q
+			}
+		}
+		h(c, err)
+	}
+}
+
+type acceptRange struct {
+	typ, subtype string
+	q            float64
+}
+
+// parseAccept 解析 Accept 请求头，忽略格式错误的媒体范围
+func parseAccept(header string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(header, ",") {
+		mt, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		typ, subtype, ok := strings.Cut(mt, "/")
+		if !ok {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		ranges = append(ranges, acceptRange{typ: typ, subtype: subtype, q: q})
+	}
+	return ranges
+}
+
+// quality 返回媒体类型在 Accept 中的 q 值，取最具体的匹配范围（type/subtype 优先于 type/*，再优先于 */*）；
+// 没有 Accept 时为 1，没有匹配时为 0
+func quality(ranges []acceptRange, mediaType string) float64 {
+	if len(ranges) == 0 {
+		return 1
+	}
+	typ, subtype, _ := strings.Cut(mediaType, "/")
+	q, specificity := 0.0, 0
+	for _, r := range ranges {
+		s := 0
+		switch {
+		case r.typ == typ && r.subtype == subtype:
+			s = 3
+		case r.typ == typ && r.subtype == "*":
+			s = 2
+		case r.typ == "*" && r.subtype == "*":
+			s = 1
+		}
+		if s > specificity {
+			q, specificity = r.q, s
+		}
+	}
+	return q
+}
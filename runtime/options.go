@@ -0,0 +1,219 @@
+package runtime
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// ServerOptions 生成的 HTTP 服务器共用的配置
+type ServerOptions struct {
+	ErrorHandler   ErrorHandler // 为空时使用 ResponseWriter.WriteError
+	ResponseWriter ResponseWriter
+	ErrorFormats   map[string]ErrorHandler // 按 Accept 选择的错误格式，见 NegotiateErrorHandler
+	BodyDecoder    *BodyDecoder
+	Marshaler      *ResponseMarshaler
+	MaxMessageSize int // 客户端流式请求和 WebSocket 中单条消息的最大字节数
+
+	Upgrader     *websocket.Upgrader // 双向流式方法的 WebSocket 升级配置
+	PingInterval time.Duration       // WebSocket keepalive 的 ping 间隔，0 表示不发送 ping
+
+	Middleware map[string][]Middleware // 中间件标签 -> 中间件，对应 (protogin.method).middleware
+	Authorizer Authorizer              // 校验 (protogin.method).auth_scopes
+
+	Routes *Routes // 注册路由使用的 dispatcher 表，见 WithRoutes
+}
+
+// Middleware 包装处理函数的中间件，next 为后续的中间件和处理函数，如
+//
+//	func(next gin.HandlerFunc) gin.HandlerFunc {
+//		return func(c *gin.Context) {
+//			start := time.Now()
+//			next(c)
+//			log.Printf("%s %d %s", c.FullPath(), c.Writer.Status(), time.Since(start))
+//		}
+//	}
+//
+// 同一 gin 路由上的多个自定义动词共用一个 gin 处理函数，标签中间件不在 gin 的处理链中，
+// 因此通过调用 next 而不是 c.Next 继续处理，不调用 next 即中止请求
+type Middleware func(next gin.HandlerFunc) gin.HandlerFunc
+
+// Before 把 gin 中间件 h 转换为 Middleware：先执行 h，h 没有调用 c.Abort 时再执行后续处理。
+// h 中的 c.Next 不会等待处理函数返回，需要在处理函数之后执行的逻辑应直接实现 Middleware
+func Before(h gin.HandlerFunc) Middleware {
+	return func(next gin.HandlerFunc) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			h(c)
+			if !c.IsAborted() {
+				next(c)
+			}
+		}
+	}
+}
+
+// Authorizer 校验请求是否具有 scopes 中的全部权限，返回的错误交给 ErrorHandler，
+// 通常为 codes.Unauthenticated 或 codes.PermissionDenied 的 gRPC 错误
+type Authorizer func(c *gin.Context, scopes []string) error
+
+// RouteOptions 一个 http 绑定在 protogin 选项中声明的中间件标签和权限范围
+type RouteOptions struct {
+	Middleware []string
+	AuthScopes []string
+}
+
+// ServerOption 定义服务器选项，同一组选项可以用于注册多个服务
+type ServerOption func(*ServerOptions)
+
+// NewServerOptions 返回应用选项后的配置，未设置的项使用默认值
+func NewServerOptions(opts ...ServerOption) *ServerOptions {
+	o := &ServerOptions{
+		ResponseWriter: EnvelopeWriter{},
+		BodyDecoder:    DefaultBodyDecoder,
+		Marshaler:      DefaultResponseMarshaler,
+		MaxMessageSize: DefaultMaxMessageSize,
+		Upgrader:       &websocket.Upgrader{},
+		PingInterval:   DefaultPingInterval,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.ErrorHandler == nil {
+		o.ErrorHandler = o.ResponseWriter.WriteError
+	}
+	if o.Routes == nil {
+		o.Routes = NewRoutes()
+	}
+	if len(o.ErrorFormats) > 0 {
+		o.ErrorHandler = NegotiateErrorHandler(o.ErrorHandler, o.ErrorFormats)
+	}
+	return o
+}
+
+// WithErrorHandler 设置自定义错误处理器
+func WithErrorHandler(h ErrorHandler) ServerOption {
+	return func(o *ServerOptions) {
+		o.ErrorHandler = h
+	}
+}
+
+// WithResponseWriter 设置响应格式，如 RawWriter、ProblemWriter、GatewayWriter 或自定义的信封
+func WithResponseWriter(w ResponseWriter) ServerOption {
+	return func(o *ServerOptions) {
+		o.ResponseWriter = w
+	}
+}
+
+// WithErrorFormat 请求的 Accept 优先选择 mediaType 时使用 h 输出错误，其余请求仍使用 ErrorHandler，如
+//
+//	runtime.WithErrorFormat(runtime.ContentTypeProblemJSON, runtime.ProblemWriter{}.WriteError)
+func WithErrorFormat(mediaType string, h ErrorHandler) ServerOption {
+	return func(o *ServerOptions) {
+		if o.ErrorFormats == nil {
+			o.ErrorFormats = make(map[string]ErrorHandler)
+		}
+		o.ErrorFormats[mediaType] = h
+	}
+}
+
+// WithMiddleware 为中间件标签提供中间件，多次调用同一标签时追加，先提供的中间件在外层
+func WithMiddleware(tag string, middleware ...Middleware) ServerOption {
+	return func(o *ServerOptions) {
+		if o.Middleware == nil {
+			o.Middleware = make(map[string][]Middleware)
+		}
+		o.Middleware[tag] = append(o.Middleware[tag], middleware...)
+	}
+}
+
+// WithAuthorizer 设置权限校验函数
+func WithAuthorizer(a Authorizer) ServerOption {
+	return func(o *ServerOptions) {
+		o.Authorizer = a
+	}
+}
+
+// WithRoutes 使用 rs 注册路由。在同一个 gin engine 上注册的多个服务（或同一服务注册到多个分组）
+// 有方法和绝对路由相同、只有自定义动词不同的路由时，应当使用同一个 Routes，如
+//
+//	routes := runtime.NewRoutes()
+//	v1.RegisterUserServiceServerHTTPServer(userSrv, r, runtime.WithRoutes(routes))
+//	v1.RegisterAdminServiceServerHTTPServer(adminSrv, r, runtime.WithRoutes(routes))
+func WithRoutes(rs *Routes) ServerOption {
+	return func(o *ServerOptions) {
+		o.Routes = rs
+	}
+}
+
+// WithBodyDecoder 设置请求体解码器，可通过 UnmarshalOptions.DiscardUnknown 控制未知字段的处理
+func WithBodyDecoder(d *BodyDecoder) ServerOption {
+	return func(o *ServerOptions) {
+		o.BodyDecoder = d
+	}
+}
+
+// WithResponseMarshaler 设置响应编码器，可配置 EmitUnpopulated、UseProtoNames、UseEnumNumbers 以及 Any 的 Resolver
+func WithResponseMarshaler(m *ResponseMarshaler) ServerOption {
+	return func(o *ServerOptions) {
+		o.Marshaler = m
+	}
+}
+
+// WithMaxMessageSize 设置客户端流式请求和 WebSocket 中单条消息的最大字节数，超过时返回 codes.ResourceExhausted
+func WithMaxMessageSize(n int) ServerOption {
+	return func(o *ServerOptions) {
+		o.MaxMessageSize = n
+	}
+}
+
+// WithWebSocketUpgrader 设置双向流式方法的 WebSocket 升级配置，默认只接受同源请求，跨域时需要设置 CheckOrigin
+func WithWebSocketUpgrader(u *websocket.Upgrader) ServerOption {
+	return func(o *ServerOptions) {
+		o.Upgrader = u
+	}
+}
+
+// WithPingInterval 设置 WebSocket keepalive 的 ping 间隔，超过两个间隔没有收到 pong 时断开连接，0 表示关闭 keepalive
+func WithPingInterval(d time.Duration) ServerOption {
+	return func(o *ServerOptions) {
+		o.PingInterval = d
+	}
+}
+
+// Wrap 按 RouteOptions 用中间件和权限校验包装处理函数，由生成的 RegisterService 调用
+//
+// 中间件按声明顺序从外到内包装，权限校验在最内层，紧挨着处理函数。
+// 标签没有对应的中间件、或声明了权限范围但没有 Authorizer 时在注册时 panic，避免静默跳过鉴权
+func (o *ServerOptions) Wrap(handler gin.HandlerFunc, route RouteOptions) gin.HandlerFunc {
+	var chain []Middleware
+	for _, tag := range route.Middleware {
+		middleware, ok := o.Middleware[tag]
+		if !ok {
+			panic(fmt.Sprintf("protogin: middleware %q is not registered, use runtime.WithMiddleware", tag))
+		}
+		chain = append(chain, middleware...)
+	}
+	if len(route.AuthScopes) > 0 {
+		if o.Authorizer == nil {
+			panic(fmt.Sprintf("protogin: auth scopes %q require runtime.WithAuthorizer", route.AuthScopes))
+		}
+		authorize, scopes := o.Authorizer, route.AuthScopes
+		errorHandler := o.ErrorHandler
+		chain = append(chain, func(next gin.HandlerFunc) gin.HandlerFunc {
+			return func(c *gin.Context) {
+				if err := authorize(c, scopes); err != nil {
+					errorHandler(c, err)
+					c.Abort()
+					return
+				}
+				next(c)
+			}
+		})
+	}
+
+	for i := len(chain) - 1; i >= 0; i-- {
+		handler = chain[i](handler)
+	}
+	return handler
+}
@@ -0,0 +1,144 @@
+package runtime
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// trace 返回记录调用顺序的中间件，name 记录在处理函数之前，name+" done" 记录在处理函数之后
+func trace(calls *[]string, name string) Middleware {
+	return func(next gin.HandlerFunc) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			*calls = append(*calls, name)
+			next(c)
+			*calls = append(*calls, name+" done")
+		}
+	}
+}
+
+// wrapped 用 Wrap 包装记录调用的处理函数并处理一个请求
+func wrapped(o *ServerOptions, route RouteOptions, calls *[]string) *httptest.ResponseRecorder {
+	h := o.Wrap(func(c *gin.Context) {
+		*calls = append(*calls, "handler")
+		c.Status(http.StatusNoContent)
+	}, route)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/v1/users/1:ban", nil)
+	h(c)
+	c.Writer.WriteHeaderNow()
+	return w
+}
+
+func TestWrapOrder(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var calls []string
+	var scopes []string
+	o := NewServerOptions(
+		WithMiddleware("logging", trace(&calls, "logging")),
+		WithMiddleware("audit", trace(&calls, "audit 1"), trace(&calls, "audit 2")),
+		WithMiddleware("audit", trace(&calls, "audit 3")),
+		WithAuthorizer(func(c *gin.Context, s []string) error {
+			calls = append(calls, "authorize")
+			scopes = s
+			return nil
+		}),
+	)
+
+	// 服务的中间件标签在方法的标签之前，与生成的 RouteOptions 一致
+	w := wrapped(o, RouteOptions{Middleware: []string{"logging", "audit"}, AuthScopes: []string{"user.admin", "user.write"}}, &calls)
+	want := []string{"logging", "audit 1", "audit 2", "audit 3", "authorize", "handler", "audit 3 done", "audit 2 done", "audit 1 done", "logging done"}
+	if w.Code != http.StatusNoContent || strings.Join(calls, ",") != strings.Join(want, ",") {
+		t.Errorf("%d %q, want 204 %q", w.Code, calls, want)
+	}
+	if strings.Join(scopes, ",") != "user.admin,user.write" {
+		t.Errorf("scopes %q", scopes)
+	}
+}
+
+func TestWrapAborts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("authorizer error", func(t *testing.T) {
+		var calls []string
+		o := NewServerOptions(
+			WithMiddleware("audit", trace(&calls, "audit")),
+			WithAuthorizer(func(*gin.Context, []string) error {
+				return status.Error(codes.PermissionDenied, "user.admin required")
+			}),
+		)
+		w := wrapped(o, RouteOptions{Middleware: []string{"audit"}, AuthScopes: []string{"user.admin"}}, &calls)
+		body := decodeBody(t, w.Body)
+		if w.Code != http.StatusForbidden || body["code"] != "PermissionDenied" {
+			t.Errorf("%d %v, want 403 PermissionDenied", w.Code, body)
+		}
+		// 审计中间件在外层，仍然看到被拒绝的请求
+		if strings.Join(calls, ",") != "audit,audit done" {
+			t.Errorf("calls %q", calls)
+		}
+	})
+
+	t.Run("Before with Abort", func(t *testing.T) {
+		var calls []string
+		o := NewServerOptions(WithMiddleware("auth", Before(func(c *gin.Context) {
+			calls = append(calls, "before")
+			c.AbortWithStatus(http.StatusUnauthorized)
+		})))
+		w := wrapped(o, RouteOptions{Middleware: []string{"auth"}}, &calls)
+		if w.Code != http.StatusUnauthorized || strings.Join(calls, ",") != "before" {
+			t.Errorf("%d %q, want 401 before", w.Code, calls)
+		}
+	})
+
+	t.Run("middleware without next", func(t *testing.T) {
+		var calls []string
+		o := NewServerOptions(WithMiddleware("block", func(gin.HandlerFunc) gin.HandlerFunc {
+			return func(c *gin.Context) {
+				calls = append(calls, "block")
+				c.Status(http.StatusTooManyRequests)
+			}
+		}))
+		w := wrapped(o, RouteOptions{Middleware: []string{"block"}}, &calls)
+		if w.Code != http.StatusTooManyRequests || strings.Join(calls, ",") != "block" {
+			t.Errorf("%d %q, want 429 block", w.Code, calls)
+		}
+	})
+}
+
+func TestWrapPanics(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []ServerOption
+		route RouteOptions
+		want  string
+	}{
+		{
+			name:  "missing middleware",
+			opts:  []ServerOption{WithMiddleware("logging")},
+			route: RouteOptions{Middleware: []string{"logging", "audit"}},
+			want:  `protogin: middleware "audit" is not registered, use runtime.WithMiddleware`,
+		},
+		{
+			name:  "missing authorizer",
+			route: RouteOptions{AuthScopes: []string{"user.admin"}},
+			want:  `protogin: auth scopes ["user.admin"] require runtime.WithAuthorizer`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != tt.want {
+					t.Errorf("panic = %v, want %s", r, tt.want)
+				}
+			}()
+			NewServerOptions(tt.opts...).Wrap(func(*gin.Context) {}, tt.route)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// Package runtime 提供 protoc-gen-gin 生成代码在运行时依赖的公共实现
+package runtime
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Pattern 路径模板中变量的段模式，如 {name=projects/*/books/*} 中的 projects/*/books/*
+//
+// 生成代码把 * 转换为 gin 的 :param，把 ** 转换为 gin 的 *catchAll，
+// 运行时再用 Pattern 按顺序把路由参数值填回通配段，重建完整的资源名
+type Pattern struct {
+	raw      string
+	segments []string
+	wildcard int // 通配段（* 和 **）的数量
+}
+
+// ParsePattern 解析变量的段模式
+func ParsePattern(s string) (*Pattern, error) {
+	if s == "" {
+		return nil, fmt.Errorf("empty pattern")
+	}
+
+	p := &Pattern{raw: s, segments: strings.Split(s, "/")}
+	for i, seg := range p.segments {
+		switch {
+		case seg == "":
+			return nil, fmt.Errorf("pattern %q: empty segment", s)
+		case seg == "**":
+			if i != len(p.segments)-1 {
+				return nil, fmt.Errorf("pattern %q: '**' must be the last segment", s)
+			}
+			p.wildcard++
+		case seg == "*":
+			p.wildcard++
+		case strings.ContainsAny(seg, "*{}="):
+			return nil, fmt.Errorf("pattern %q: invalid segment %q", s, seg)
+		}
+	}
+
+	return p, nil
+}
+
+// MustPattern 同 ParsePattern，解析失败时 panic，用于生成代码中的包级变量
+func MustPattern(s string) *Pattern {
+	p, err := ParsePattern(s)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// String 返回原始的段模式
+func (p *Pattern) String() string {
+	return p.raw
+}
+
+// Build 按顺序用路由参数值填充通配段，重建并校验完整的资源名
+//
+// 路由参数与模式不匹配（如单段通配中出现 /）时返回 NotFound，
+// 通配段为空或包含空段时返回 InvalidArgument
+func (p *Pattern) Build(values ...string) (string, error) {
+	if len(values) != p.wildcard {
+		return "", status.Errorf(codes.Internal, "pattern %q expects %d values, got %d", p.raw, p.wildcard, len(values))
+	}
+
+	parts := make([]string, 0, len(p.segments))
+	next := 0
+	for _, seg := range p.segments {
+		switch seg {
+		case "*":
+			v := values[next]
+			next++
+			if v == "" {
+				return "", status.Errorf(codes.InvalidArgument, "path does not match pattern %q: empty segment", p.raw)
+			}
+			if strings.Contains(v, "/") {
+				return "", status.Errorf(codes.NotFound, "path does not match pattern %q", p.raw)
+			}
+			parts = append(parts, v)
+		case "**":
+			// gin 的 catch-all 参数以 / 开头，** 可以匹配零个或多个段
+			v := strings.TrimPrefix(values[next], "/")
+			next++
+			if v == "" {
+				continue
+			}
+			for _, s := range strings.Split(v, "/") {
+				if s == "" {
+					return "", status.Errorf(codes.InvalidArgument, "path does not match pattern %q: empty segment", p.raw)
+				}
+			}
+			parts = append(parts, v)
+		default:
+			parts = append(parts, seg)
+		}
+	}
+
+	name := strings.Join(parts, "/")
+	if name == "" {
+		return "", status.Errorf(codes.InvalidArgument, "path does not match pattern %q: empty value", p.raw)
+	}
+	return name, nil
+}
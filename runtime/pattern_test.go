@@ -0,0 +1,87 @@
+package runtime
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestParsePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		err     string // 为空表示解析成功
+	}{
+		{"projects/*", ""},
+		{"projects/*/books/*", ""},
+		{"shelves/**", ""},
+		{"*", ""},
+		{"**", ""},
+		{"", "empty pattern"},
+		{"projects//*", `pattern "projects//*": empty segment`},
+		{"projects/*/", `pattern "projects/*/": empty segment`},
+		{"**/books", `pattern "**/books": '**' must be the last segment`},
+		{"projects/b*", `pattern "projects/b*": invalid segment "b*"`},
+		{"projects/{id}", `pattern "projects/{id}": invalid segment "{id}"`},
+	}
+	for _, tt := range tests {
+		p, err := ParsePattern(tt.pattern)
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("ParsePattern(%q): %v", tt.pattern, err)
+		case tt.err == "" && p.String() != tt.pattern:
+			t.Errorf("ParsePattern(%q).String() = %q", tt.pattern, p.String())
+		case tt.err != "" && (err == nil || err.Error() != tt.err):
+			t.Errorf("ParsePattern(%q) error %v, want %q", tt.pattern, err, tt.err)
+		}
+	}
+}
+
+func TestMustPatternPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("MustPattern did not panic")
+		}
+	}()
+	MustPattern("**/books")
+}
+
+func TestPatternBuild(t *testing.T) {
+	tests := []struct {
+		pattern string
+		values  []string
+		want    string
+		code    codes.Code // 为 OK 表示构建成功
+	}{
+		{"projects/*", []string{"p1"}, "projects/p1", codes.OK},
+		{"projects/*/books/*", []string{"p1", "b2"}, "projects/p1/books/b2", codes.OK},
+		{"*", []string{"p1"}, "p1", codes.OK},
+		// gin 的 catch-all 参数以 / 开头
+		{"shelves/**", []string{"/a/b/c"}, "shelves/a/b/c", codes.OK},
+		{"shelves/**", []string{"/"}, "shelves", codes.OK},
+		{"**", []string{"/a"}, "a", codes.OK},
+
+		// 参数个数与通配段数量不一致是生成代码的错误
+		{"projects/*/books/*", []string{"p1"}, "", codes.Internal},
+		{"projects/*", []string{"p1", "b2"}, "", codes.Internal},
+		{"projects/*", nil, "", codes.Internal},
+
+		{"projects/*", []string{""}, "", codes.InvalidArgument},
+		{"projects/*/books/*", []string{"p1", ""}, "", codes.InvalidArgument},
+		{"shelves/**", []string{"/a//b"}, "", codes.InvalidArgument},
+		{"shelves/**", []string{"/a/"}, "", codes.InvalidArgument},
+		{"**", []string{"/"}, "", codes.InvalidArgument},
+		{"projects/*", []string{"a/b"}, "", codes.NotFound},
+	}
+	for _, tt := range tests {
+		got, err := MustPattern(tt.pattern).Build(tt.values...)
+		if code := status.Code(err); code != tt.code || got != tt.want {
+			t.Errorf("%q.Build(%q) = %q, %v; want %q, %v", tt.pattern, tt.values, got, err, tt.want, tt.code)
+			continue
+		}
+		if err != nil && !strings.Contains(err.Error(), tt.pattern) {
+			t.Errorf("%q.Build(%q) error %q does not name the pattern", tt.pattern, tt.values, err)
+		}
+	}
+}
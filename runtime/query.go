@@ -0,0 +1,303 @@
+package runtime
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// PopulateQueryParameters 按 grpc-gateway 的规则把 query 参数解析到请求消息中
+//
+//   - 参数名可以是字段的 proto 名称或 json_name，嵌套消息使用 a.b.c 形式
+//   - repeated 字段通过重复参数赋值，如 ids=1&ids=2
+//   - map 字段使用 labels[key]=value 形式
+//   - 枚举可以使用名称或数字，bytes 使用 base64
+//   - 支持 Timestamp、Duration、FieldMask（逗号分隔）以及各类 wrapper 类型
+//
+// filter 为不从 query 中解析的字段路径（路径参数和请求体字段），这些参数及其子字段会被忽略。
+// 未知参数、重复的单值参数以及同一 oneof 中的多个字段会返回 400 BindError
+func PopulateQueryParameters(msg proto.Message, values url.Values, filter ...string) error {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	d := &queryDecoder{
+		source: "query parameter",
+		filter: filter,
+		seen:   make(map[string]string),
+	}
+	for _, key := range keys {
+		if err := d.populate(msg.ProtoReflect(), key, values[key]); err != nil {
+			return d.bindError(key, err)
+		}
+	}
+	return nil
+}
+
+// queryDecoder 按字段描述符解析字符串参数，也用于 multipart 表单中的普通字段
+type queryDecoder struct {
+	source string // 错误信息中参数的名称，如 query parameter
+	filter []string
+	seen   map[string]string // 已赋值的单值字段路径 -> 参数名，用于检测重复参数
+}
+
+// bindError 参数 key 解析失败时的 400 BindError，err 由 populate 生成，只包含参数名和期望的类型
+func (d *queryDecoder) bindError(key string, err error) *BindError {
+	return &BindError{Status: http.StatusBadRequest, Source: d.source, Field: key, Message: err.Error(), Err: err}
+}
+
+func (d *queryDecoder) populate(m protoreflect.Message, key string, values []string) error {
+	fieldPath, mapKey, isMapEntry, err := splitQueryKey(key)
+	if err != nil {
+		return fmt.Errorf("invalid %s %q", d.source, key)
+	}
+
+	fields, err := d.lookup(m.Descriptor(), key, fieldPath)
+	if err != nil {
+		return err
+	}
+	protoPath := make([]string, 0, len(fields))
+	for _, fd := range fields {
+		protoPath = append(protoPath, string(fd.Name()))
+	}
+	if d.filtered(protoPath) {
+		return nil
+	}
+	if m, err = d.parent(m, fields, key); err != nil {
+		return err
+	}
+
+	fd := fields[len(fields)-1]
+	path := strings.Join(protoPath, ".")
+	switch {
+	case fd.IsMap():
+		if !isMapEntry {
+			return fmt.Errorf("%s %q: map field must be set as %s[key]=value", d.source, key, fieldPath)
+		}
+		k, err := parseScalar(fd.MapKey(), mapKey)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: invalid map key: %w", d.source, key, err)
+		}
+		// 按解析后的 key 检测重复，如 codes[1] 和 codes[01]
+		path += "[" + k.MapKey().String() + "]"
+		if err := d.markSeen(path, key, values); err != nil {
+			return err
+		}
+		v, err := parseScalar(fd.MapValue(), values[0])
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: %w", d.source, key, err)
+		}
+		m.Mutable(fd).Map().Set(k.MapKey(), v)
+	case isMapEntry:
+		return fmt.Errorf("%s %q: field %q is not a map", d.source, key, fd.Name())
+	case fd.IsList():
+		list := m.Mutable(fd).List()
+		for _, raw := range values {
+			v, err := parseValue(list.NewElement, fd, raw)
+			if err != nil {
+				return fmt.Errorf("invalid %s %q: %w", d.source, key, err)
+			}
+			list.Append(v)
+		}
+	default:
+		if err := d.markSeen(path, key, values); err != nil {
+			return err
+		}
+		if err := d.checkOneof(m, fd, key); err != nil {
+			return err
+		}
+		v, err := parseValue(func() protoreflect.Value { return m.NewField(fd) }, fd, values[0])
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: %w", d.source, key, err)
+		}
+		m.Set(fd, v)
+	}
+	return nil
+}
+
+// lookup 逐级解析字段路径，返回路径上的字段
+func (d *queryDecoder) lookup(md protoreflect.MessageDescriptor, key, fieldPath string) ([]protoreflect.FieldDescriptor, error) {
+	names := strings.Split(fieldPath, ".")
+	fields := make([]protoreflect.FieldDescriptor, 0, len(names))
+	for i, name := range names {
+		fd := findField(md, name)
+		if fd == nil {
+			return nil, fmt.Errorf("unknown %s %q", d.source, key)
+		}
+		fields = append(fields, fd)
+		if i == len(names)-1 {
+			break
+		}
+		if fd.Message() == nil || fd.IsList() || fd.IsMap() || isWellKnownScalar(fd.Message()) {
+			return nil, fmt.Errorf("%s %q: field %q is not a message", d.source, key, fd.Name())
+		}
+		md = fd.Message()
+	}
+	return fields, nil
+}
+
+// parent 沿途创建中间消息，返回最终字段所在的消息
+func (d *queryDecoder) parent(m protoreflect.Message, fields []protoreflect.FieldDescriptor, key string) (protoreflect.Message, error) {
+	for _, fd := range fields[:len(fields)-1] {
+		if err := d.checkOneof(m, fd, key); err != nil {
+			return nil, err
+		}
+		m = m.Mutable(fd).Message()
+	}
+	return m, nil
+}
+
+// filtered 判断字段路径或其上级路径是否在 filter 中
+func (d *queryDecoder) filtered(protoPath []string) bool {
+	for i := range protoPath {
+		prefix := strings.Join(protoPath[:i+1], ".")
+		for _, f := range d.filter {
+			if f == prefix {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// markSeen 检测单值字段的重复赋值，包括通过 proto 名称和 json_name 分别赋值的情况
+func (d *queryDecoder) markSeen(path, key string, values []string) error {
+	if len(values) > 1 {
+		return fmt.Errorf("%s %q is not repeated but has %d values", d.source, key, len(values))
+	}
+	if prev, ok := d.seen[path]; ok {
+		return fmt.Errorf("%s %q duplicates %q", d.source, key, prev)
+	}
+	d.seen[path] = key
+	return nil
+}
+
+// splitQueryKey 拆分参数名中的 map key，如 a.labels[env] -> a.labels, env
+func splitQueryKey(key string) (fieldPath, mapKey string, isMapEntry bool, err error) {
+	i := strings.IndexByte(key, '[')
+	if i < 0 {
+		return key, "", false, nil
+	}
+	if i == 0 || !strings.HasSuffix(key, "]") {
+		return "", "", false, fmt.Errorf("invalid key %q", key)
+	}
+	return key[:i], key[i+1 : len(key)-1], true, nil
+}
+
+// findField 按 proto 名称或 json_name 查找字段
+func findField(md protoreflect.MessageDescriptor, name string) protoreflect.FieldDescriptor {
+	if fd := md.Fields().ByName(protoreflect.Name(name)); fd != nil {
+		return fd
+	}
+	return md.Fields().ByJSONName(name)
+}
+
+// checkOneof 同一个 oneof 中只允许设置一个字段
+func (d *queryDecoder) checkOneof(m protoreflect.Message, fd protoreflect.FieldDescriptor, key string) error {
+	od := fd.ContainingOneof()
+	if od == nil || od.IsSynthetic() {
+		return nil
+	}
+	if set := m.WhichOneof(od); set != nil && set.Number() != fd.Number() {
+		return fmt.Errorf("%s %q: oneof %q already has field %q set", d.source, key, od.Name(), set.Name())
+	}
+	return nil
+}
+
+// isWellKnownScalar 判断消息是否为可以直接从字符串解析的 well-known 类型
+func isWellKnownScalar(md protoreflect.MessageDescriptor) bool {
+	_, ok := wellKnownParsers[md.FullName()]
+	return ok
+}
+
+// wellKnownParsers 可以从单个 query 参数值解析的 well-known 类型
+var wellKnownParsers = map[protoreflect.FullName]func(string) (proto.Message, error){
+	"google.protobuf.Timestamp": func(s string) (proto.Message, error) { return Timestamp(s) },
+	"google.protobuf.Duration":  func(s string) (proto.Message, error) { return Duration(s) },
+	"google.protobuf.FieldMask": func(s string) (proto.Message, error) {
+		fm := &fieldmaskpb.FieldMask{}
+		for _, p := range strings.Split(s, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				fm.Paths = append(fm.Paths, p)
+			}
+		}
+		return fm, nil
+	},
+	"google.protobuf.StringValue": func(s string) (proto.Message, error) { return StringValue(s) },
+	"google.protobuf.BoolValue":   func(s string) (proto.Message, error) { return BoolValue(s) },
+	"google.protobuf.Int32Value":  func(s string) (proto.Message, error) { return Int32Value(s) },
+	"google.protobuf.Int64Value":  func(s string) (proto.Message, error) { return Int64Value(s) },
+	"google.protobuf.UInt32Value": func(s string) (proto.Message, error) { return UInt32Value(s) },
+	"google.protobuf.UInt64Value": func(s string) (proto.Message, error) { return UInt64Value(s) },
+	"google.protobuf.FloatValue":  func(s string) (proto.Message, error) { return FloatValue(s) },
+	"google.protobuf.DoubleValue": func(s string) (proto.Message, error) { return DoubleValue(s) },
+	"google.protobuf.BytesValue":  func(s string) (proto.Message, error) { return BytesValue(s) },
+}
+
+// parseValue 解析字段（或 repeated 字段的元素）的值，newValue 用于创建字段自身类型的消息值
+func parseValue(newValue func() protoreflect.Value, fd protoreflect.FieldDescriptor, raw string) (protoreflect.Value, error) {
+	if fd.Message() == nil {
+		return parseScalar(fd, raw)
+	}
+
+	parse, ok := wellKnownParsers[fd.Message().FullName()]
+	if !ok {
+		return protoreflect.Value{}, fmt.Errorf("message field %q cannot be set from a single value", fd.Name())
+	}
+	v, err := parse(raw)
+	if err != nil {
+		return protoreflect.Value{}, err
+	}
+
+	dst := newValue()
+	proto.Merge(dst.Message().Interface(), v)
+	return dst, nil
+}
+
+// parseScalar 解析标量和枚举值
+func parseScalar(fd protoreflect.FieldDescriptor, raw string) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(raw), nil
+	case protoreflect.BoolKind:
+		v, err := Bool(raw)
+		return protoreflect.ValueOfBool(v), err
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		v, err := Int32(raw)
+		return protoreflect.ValueOfInt32(v), err
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		v, err := Int64(raw)
+		return protoreflect.ValueOfInt64(v), err
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		v, err := Uint32(raw)
+		return protoreflect.ValueOfUint32(v), err
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		v, err := Uint64(raw)
+		return protoreflect.ValueOfUint64(v), err
+	case protoreflect.FloatKind:
+		v, err := Float32(raw)
+		return protoreflect.ValueOfFloat32(v), err
+	case protoreflect.DoubleKind:
+		v, err := Float64(raw)
+		return protoreflect.ValueOfFloat64(v), err
+	case protoreflect.BytesKind:
+		v, err := Bytes(raw)
+		return protoreflect.ValueOfBytes(v), err
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByName(protoreflect.Name(raw)); ev != nil {
+			return protoreflect.ValueOfEnum(ev.Number()), nil
+		}
+		v, err := Enum(raw, nil)
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(v)), err
+	default:
+		return protoreflect.Value{}, fmt.Errorf("unsupported field kind %s", fd.Kind())
+	}
+}
@@ -0,0 +1,199 @@
+package runtime
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/JarrettGuo/protogin/internal/testproto"
+
+	_ "google.golang.org/protobuf/types/known/fieldmaskpb"
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// queryProto 覆盖 query 参数各种规则的测试消息
+//
+//	message Query {
+//	  enum Status { STATUS_UNSPECIFIED = 0; ACTIVE = 1; }
+//	  message Inner { string id = 1; Inner child = 2; repeated string tags = 3; }
+//	  string name = 1;
+//	  int32 page_size = 2;
+//	  string display = 3 [json_name = "label"];
+//	  repeated int64 ids = 4;
+//	  map<string, int32> labels = 5;
+//	  Status status = 6;
+//	  Inner inner = 7;
+//	  oneof contact { string email = 8; Inner person = 9; }
+//	  bytes data = 10;
+//	  google.protobuf.Timestamp since = 11;
+//	  google.protobuf.Duration ttl = 12;
+//	  google.protobuf.FieldMask mask = 13;
+//	  google.protobuf.Int64Value limit = 14;
+//	  repeated google.protobuf.StringValue aliases = 15;
+//	  bool active = 16;
+//	  map<int32, string> codes = 17;
+//	}
+const queryProto = `
+name: "query_test.proto"
+package: "protogin.test"
+syntax: "proto3"
+dependency: ["google/protobuf/timestamp.proto", "google/protobuf/duration.proto", "google/protobuf/field_mask.proto", "google/protobuf/wrappers.proto"]
+message_type {
+  name: "Query"
+  field { name: "name" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING }
+  field { name: "page_size" number: 2 label: LABEL_OPTIONAL type: TYPE_INT32 }
+  field { name: "display" number: 3 label: LABEL_OPTIONAL type: TYPE_STRING json_name: "label" }
+  field { name: "ids" number: 4 label: LABEL_REPEATED type: TYPE_INT64 }
+  field { name: "labels" number: 5 label: LABEL_REPEATED type: TYPE_MESSAGE type_name: ".protogin.test.Query.LabelsEntry" }
+  field { name: "status" number: 6 label: LABEL_OPTIONAL type: TYPE_ENUM type_name: ".protogin.test.Query.Status" }
+  field { name: "inner" number: 7 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".protogin.test.Query.Inner" }
+  field { name: "email" number: 8 label: LABEL_OPTIONAL type: TYPE_STRING oneof_index: 0 }
+  field { name: "person" number: 9 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".protogin.test.Query.Inner" oneof_index: 0 }
+  field { name: "data" number: 10 label: LABEL_OPTIONAL type: TYPE_BYTES }
+  field { name: "since" number: 11 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.Timestamp" }
+  field { name: "ttl" number: 12 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.Duration" }
+  field { name: "mask" number: 13 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.FieldMask" }
+  field { name: "limit" number: 14 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.Int64Value" }
+  field { name: "aliases" number: 15 label: LABEL_REPEATED type: TYPE_MESSAGE type_name: ".google.protobuf.StringValue" }
+  field { name: "active" number: 16 label: LABEL_OPTIONAL type: TYPE_BOOL }
+  field { name: "codes" number: 17 label: LABEL_REPEATED type: TYPE_MESSAGE type_name: ".protogin.test.Query.CodesEntry" }
+  nested_type {
+    name: "Inner"
+    field { name: "id" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING }
+    field { name: "child" number: 2 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".protogin.test.Query.Inner" }
+    field { name: "tags" number: 3 label: LABEL_REPEATED type: TYPE_STRING }
+  }
+  nested_type {
+    name: "LabelsEntry"
+    field { name: "key" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING }
+    field { name: "value" number: 2 label: LABEL_OPTIONAL type: TYPE_INT32 }
+    options { map_entry: true }
+  }
+  nested_type {
+    name: "CodesEntry"
+    field { name: "key" number: 1 label: LABEL_OPTIONAL type: TYPE_INT32 }
+    field { name: "value" number: 2 label: LABEL_OPTIONAL type: TYPE_STRING }
+    options { map_entry: true }
+  }
+  enum_type {
+    name: "Status"
+    value { name: "STATUS_UNSPECIFIED" number: 0 }
+    value { name: "ACTIVE" number: 1 }
+  }
+  oneof_decl { name: "contact" }
+}
+`
+
+func TestPopulateQueryParameters(t *testing.T) {
+	md := testproto.Message(t, queryProto, "Query")
+
+	tests := []struct {
+		name   string
+		query  string
+		filter []string
+		want   string // 期望的消息，protojson 格式
+	}{
+		{"proto name", "page_size=10", nil, `{"pageSize":10}`},
+		{"json name", "pageSize=10", nil, `{"pageSize":10}`},
+		{"custom json_name", "label=x", nil, `{"label":"x"}`},
+		{"custom json_name by proto name", "display=x", nil, `{"label":"x"}`},
+		{"repeated", "ids=1&ids=2&ids=3", nil, `{"ids":["1","2","3"]}`},
+		{"map", "labels[env]=1&labels[tier]=2", nil, `{"labels":{"env":1,"tier":2}}`},
+		{"map with int key", "codes[404]=missing", nil, `{"codes":{"404":"missing"}}`},
+		{"enum name", "status=ACTIVE", nil, `{"status":"ACTIVE"}`},
+		{"enum number", "status=1", nil, `{"status":"ACTIVE"}`},
+		{"bytes std base64", "data=aGk=", nil, `{"data":"aGk="}`},
+		{"bytes url base64 without padding", "data=-_8", nil, `{"data":"+/8="}`},
+		{"nested", "inner.id=a&inner.child.id=b", nil, `{"inner":{"id":"a","child":{"id":"b"}}}`},
+		{"nested repeated", "inner.tags=a&inner.tags=b", nil, `{"inner":{"tags":["a","b"]}}`},
+		{"timestamp", "since=2024-01-02T03:04:05.5Z", nil, `{"since":"2024-01-02T03:04:05.500Z"}`},
+		{"duration", "ttl=1.5s", nil, `{"ttl":"1.500s"}`},
+		{"field mask", "mask=name, page_size,,inner.id", nil, `{"mask":"name,pageSize,inner.id"}`},
+		{"wrapper", "limit=42", nil, `{"limit":"42"}`},
+		{"repeated wrapper", "aliases=a&aliases=b", nil, `{"aliases":["a","b"]}`},
+		{"oneof", "email=a@b.c", nil, `{"email":"a@b.c"}`},
+		{"oneof message", "person.id=p", nil, `{"person":{"id":"p"}}`},
+		{"filtered field", "name=a&page_size=1", []string{"name"}, `{"pageSize":1}`},
+		{"filtered by json name", "pageSize=1", []string{"page_size"}, `{}`},
+		{"filtered prefix", "inner.id=a&inner.child.id=b&name=n", []string{"inner"}, `{"name":"n"}`},
+		{"filtered nested path only", "inner.id=a&inner.child.id=b", []string{"inner.child"}, `{"inner":{"id":"a"}}`},
+		{"filter is not a string prefix", "inner.id=a", []string{"inn"}, `{"inner":{"id":"a"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := dynamicpb.NewMessage(md)
+			if err := PopulateQueryParameters(got, values, tt.filter...); err != nil {
+				t.Fatalf("PopulateQueryParameters: %v", err)
+			}
+			want := dynamicpb.NewMessage(md)
+			if err := protojson.Unmarshal([]byte(tt.want), want); err != nil {
+				t.Fatal(err)
+			}
+			if !proto.Equal(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestPopulateQueryParametersErrors(t *testing.T) {
+	md := testproto.Message(t, queryProto, "Query")
+
+	tests := []struct {
+		name  string
+		query string
+		field string
+		msg   string
+	}{
+		{"unknown", "nope=1", "nope", `unknown query parameter "nope"`},
+		{"unknown nested", "inner.nope=1", "inner.nope", `unknown query parameter "inner.nope"`},
+		{"not a message", "name.id=1", "name.id", `query parameter "name.id": field "name" is not a message`},
+		{"through repeated", "aliases.value=1", "aliases.value", `query parameter "aliases.value": field "aliases" is not a message`},
+		{"through well-known scalar", "limit.value=1", "limit.value", `query parameter "limit.value": field "limit" is not a message`},
+		{"repeated single field", "name=a&name=b", "name", `query parameter "name" is not repeated but has 2 values`},
+		{"proto and json name", "pageSize=1&page_size=2", "page_size", `query parameter "page_size" duplicates "pageSize"`},
+		{"repeated map key", "labels[a]=1&labels[a]=2", "labels[a]", `query parameter "labels[a]" is not repeated but has 2 values`},
+		{"same map key spelled twice", "codes[01]=a&codes[1]=b", "codes[1]", `query parameter "codes[1]" duplicates "codes[01]"`},
+		{"map without key", "labels=1", "labels", `query parameter "labels": map field must be set as labels[key]=value`},
+		{"key on non-map", "name[a]=1", "name[a]", `query parameter "name[a]": field "name" is not a map`},
+		{"bad map key", "codes[x]=a", "codes[x]", `invalid query parameter "codes[x]": invalid map key: want int32`},
+		{"bad map value", "labels[a]=x", "labels[a]", `invalid query parameter "labels[a]": want int32`},
+		{"malformed key", "[a]=1", "[a]", `invalid query parameter "[a]"`},
+		{"unterminated key", "labels[a=1", "labels[a", `invalid query parameter "labels[a"`},
+		{"oneof conflict", "email=a&person.id=p", "person.id", `query parameter "person.id": oneof "contact" already has field "email" set`},
+		{"int", "page_size=x", "page_size", `invalid query parameter "page_size": want int32`},
+		{"repeated int", "ids=1&ids=x", "ids", `invalid query parameter "ids": want int64`},
+		{"bool", "active=yes", "active", `invalid query parameter "active": want bool`},
+		{"enum", "status=BOGUS", "status", `invalid query parameter "status": want enum name or number`},
+		{"bytes", "data=***", "data", `invalid query parameter "data": want base64 bytes`},
+		{"timestamp", "since=yesterday", "since", `invalid query parameter "since": want RFC 3339 timestamp`},
+		{"duration", "ttl=soon", "ttl", `invalid query parameter "ttl": want duration such as 1.5s`},
+		{"wrapper", "limit=x", "limit", `invalid query parameter "limit": want int64`},
+		{"message", "inner=x", "inner", `invalid query parameter "inner": message field "inner" cannot be set from a single value`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = PopulateQueryParameters(dynamicpb.NewMessage(md), values)
+			var be *BindError
+			if !errors.As(err, &be) {
+				t.Fatalf("error %v (%T), want *BindError", err, err)
+			}
+			if be.Status != http.StatusBadRequest || be.Source != "query parameter" || be.Field != tt.field || be.Message != tt.msg {
+				t.Errorf("got %d %q %q %q, want 400 %q %q", be.Status, be.Source, be.Field, be.Message, tt.field, tt.msg)
+			}
+		})
+	}
+}
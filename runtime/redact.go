@@ -0,0 +1,52 @@
+package runtime
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/JarrettGuo/protogin/gen/protogin"
+)
+
+// Redact 返回清除了 (protogin.field).redact 字段（包括嵌套消息、repeated 和 map 中的消息）的副本，
+// 不修改原消息。生成的代码在编码响应前调用，也可以用于日志
+func Redact[T proto.Message](msg T) T {
+	if !msg.ProtoReflect().IsValid() {
+		return msg
+	}
+	clone := proto.Clone(msg).(T)
+	redact(clone.ProtoReflect())
+	return clone
+}
+
+func redact(m protoreflect.Message) {
+	var cleared []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if opts, ok := proto.GetExtension(fd.Options(), protogin.E_Field).(*protogin.FieldOptions); ok && opts.GetRedact() {
+			cleared = append(cleared, fd)
+			return true
+		}
+
+		switch {
+		case fd.IsMap():
+			if fd.MapValue().Message() != nil {
+				v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+					redact(mv.Message())
+					return true
+				})
+			}
+		case fd.IsList():
+			if fd.Message() != nil {
+				list := v.List()
+				for i := 0; i < list.Len(); i++ {
+					redact(list.Get(i).Message())
+				}
+			}
+		case fd.Message() != nil:
+			redact(v.Message())
+		}
+		return true
+	})
+	for _, fd := range cleared {
+		m.Clear(fd)
+	}
+}
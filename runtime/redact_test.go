@@ -0,0 +1,109 @@
+package runtime
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/JarrettGuo/protogin/internal/testproto"
+)
+
+// accountProto 带有 (protogin.field).redact 字段的测试消息
+//
+//	message Account {
+//	  message Key { string id = 1; string secret = 2 [(protogin.field).redact = true]; }
+//	  string name = 1;
+//	  string password = 2 [(protogin.field).redact = true];
+//	  Key key = 3;
+//	  repeated Key keys = 4;
+//	  map<string, Key> named = 5;
+//	  Key backup = 6 [(protogin.field).redact = true];
+//	  string operator = 7 [(protogin.field).header = "X-Operator"];
+//	  string session = 8 [(protogin.field).cookie = "sid"];
+//	}
+const accountProto = `
+name: "redact_test.proto"
+package: "protogin.test"
+syntax: "proto3"
+dependency: ["protogin/options.proto"]
+message_type {
+  name: "Account"
+  field { name: "name" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING json_name: "name" }
+  field { name: "password" number: 2 label: LABEL_OPTIONAL type: TYPE_STRING json_name: "password" options { [protogin.field] { redact: true } } }
+  field { name: "key" number: 3 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".protogin.test.Account.Key" json_name: "key" }
+  field { name: "keys" number: 4 label: LABEL_REPEATED type: TYPE_MESSAGE type_name: ".protogin.test.Account.Key" json_name: "keys" }
+  field { name: "named" number: 5 label: LABEL_REPEATED type: TYPE_MESSAGE type_name: ".protogin.test.Account.NamedEntry" json_name: "named" }
+  field { name: "backup" number: 6 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".protogin.test.Account.Key" json_name: "backup" options { [protogin.field] { redact: true } } }
+  field { name: "operator" number: 7 label: LABEL_OPTIONAL type: TYPE_STRING json_name: "operator" options { [protogin.field] { header: "X-Operator" } } }
+  field { name: "session" number: 8 label: LABEL_OPTIONAL type: TYPE_STRING json_name: "session" options { [protogin.field] { cookie: "sid" } } }
+  nested_type {
+    name: "Key"
+    field { name: "id" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING json_name: "id" }
+    field { name: "secret" number: 2 label: LABEL_OPTIONAL type: TYPE_STRING json_name: "secret" options { [protogin.field] { redact: true } } }
+  }
+  nested_type {
+    name: "NamedEntry"
+    field { name: "key" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING json_name: "key" }
+    field { name: "value" number: 2 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".protogin.test.Account.Key" json_name: "value" }
+    options { map_entry: true }
+  }
+}
+`
+
+// accounts 返回按 JSON 构造 Account 消息的函数，构造的消息使用同一个描述符，可以用 proto.Equal 比较
+func accounts(t *testing.T) func(data string) *dynamicpb.Message {
+	t.Helper()
+	md := testproto.Message(t, accountProto, "Account")
+	return func(data string) *dynamicpb.Message {
+		t.Helper()
+		msg := dynamicpb.NewMessage(md)
+		if err := protojson.Unmarshal([]byte(data), msg); err != nil {
+			t.Fatalf("unmarshal %s: %v", data, err)
+		}
+		return msg
+	}
+}
+
+func TestRedact(t *testing.T) {
+	const full = `{
+		"name": "alice", "password": "p",
+		"key": {"id": "k1", "secret": "s1"},
+		"keys": [{"id": "k2", "secret": "s2"}, {"id": "k3"}],
+		"named": {"a": {"id": "k4", "secret": "s4"}},
+		"backup": {"id": "k5", "secret": "s5"},
+		"operator": "bob"
+	}`
+	account := accounts(t)
+	msg := account(full)
+	got := Redact(msg)
+
+	want := account(`{
+		"name": "alice",
+		"key": {"id": "k1"},
+		"keys": [{"id": "k2"}, {"id": "k3"}],
+		"named": {"a": {"id": "k4"}},
+		"operator": "bob"
+	}`)
+	if !proto.Equal(got, want) {
+		t.Errorf("Redact = %v, want %v", got, want)
+	}
+	// 不修改原消息
+	if !proto.Equal(msg, account(full)) {
+		t.Errorf("original message modified: %v", msg)
+	}
+}
+
+func TestRedactWithoutRedactedFields(t *testing.T) {
+	msg := wrapperspb.String("secret")
+	if got := Redact(msg); !proto.Equal(got, msg) {
+		t.Errorf("Redact = %v, want %v", got, msg)
+	}
+
+	var nilMsg *wrapperspb.StringValue
+	if got := Redact(nilMsg); got != nil {
+		t.Errorf("Redact(nil) = %v, want nil", got)
+	}
+}
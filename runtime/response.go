@@ -0,0 +1,144 @@
+package runtime
+
+import (
+	"encoding/json"
+	"net/http"
+
+	perrors "github.com/JarrettGuo/protogin/errors"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+)
+
+// ResponseWriter 决定成功响应和错误响应的格式（响应信封）
+//
+// 生成的处理函数把编码后的响应交给 WriteResponse；没有设置 ErrorHandler 时，错误交给 WriteError。
+// 实现自定义信封时可以用 ErrorStatus 得到错误对应的 HTTP 状态码、错误码和错误信息，用 ErrorDetails 得到错误详情
+type ResponseWriter interface {
+	// WriteResponse 写入成功响应，data 为编码后的响应消息或 response_body 字段
+	WriteResponse(c *gin.Context, status int, data any)
+	// WriteError 写入错误响应
+	WriteError(c *gin.Context, err error)
+}
+
+// EnvelopeWriter 统一响应格式（默认）
+//
+//	{"code": "SUCCESS", "message": "ok", "data": {...}, "success": true}
+//	{"code": "NotFound", "message": "user not found", "success": false}
+//
+// 错误携带 google.rpc.Status 详情时，错误响应中还有 details 和 fields，见 ErrorDetails
+type EnvelopeWriter struct{}
+
+func (EnvelopeWriter) WriteResponse(c *gin.Context, status int, data any) {
+	c.JSON(status, gin.H{
+		"code":    "SUCCESS",
+		"message": "ok",
+		"data":    data,
+		"success": true,
+	})
+}
+
+func (EnvelopeWriter) WriteError(c *gin.Context, err error) {
+	status, code, message := ErrorStatus(err)
+	body := gin.H{
+		"code":    code,
+		"message": message,
+		"success": false,
+	}
+	writeErrorDetails(c, err, body)
+	c.JSON(status, body)
+}
+
+// RawWriter 直接返回响应消息，不包装信封（插件参数 envelope=false）
+//
+//	{...}
+//	{"code": "NotFound", "message": "user not found"}
+type RawWriter struct{}
+
+func (RawWriter) WriteResponse(c *gin.Context, status int, data any) {
+	c.JSON(status, data)
+}
+
+func (RawWriter) WriteError(c *gin.Context, err error) {
+	status, code, message := ErrorStatus(err)
+	body := gin.H{
+		"code":    code,
+		"message": message,
+	}
+	writeErrorDetails(c, err, body)
+	c.JSON(status, body)
+}
+
+// ContentTypeProblemJSON RFC 9457 错误响应的 Content-Type
+const ContentTypeProblemJSON = "application/problem+json"
+
+// ProblemWriter 直接返回响应消息，错误按 RFC 9457 输出为 application/problem+json
+//
+//	{...}
+//	{"type": "about:blank", "title": "Not Found", "status": 404, "detail": "user not found",
+//	 "instance": "/api/v1/users/1", "code": "NotFound"}
+//
+// code、details 和 fields 作为扩展成员输出
+type ProblemWriter struct {
+	// TypeBase 不为空时 type 为 TypeBase + 错误码，如 https://example.com/errors/NotFound；为空时为 about:blank
+	TypeBase string
+}
+
+func (ProblemWriter) WriteResponse(c *gin.Context, status int, data any) {
+	c.JSON(status, data)
+}
+
+func (w ProblemWriter) WriteError(c *gin.Context, err error) {
+	status, code, message := ErrorStatus(err)
+	typ := "about:blank"
+	if w.TypeBase != "" {
+		typ = w.TypeBase + code
+	}
+	body := gin.H{
+		"type":     typ,
+		"title":    http.StatusText(status),
+		"status":   status,
+		"detail":   message,
+		"instance": c.Request.URL.Path,
+		"code":     code,
+	}
+	writeErrorDetails(c, err, body)
+	c.Header("Content-Type", ContentTypeProblemJSON)
+	c.JSON(status, body)
+}
+
+// GatewayWriter 与 grpc-gateway 的默认格式相同：直接返回响应消息，错误为
+//
+//	{"code": 5, "message": "user not found", "details": [...]}
+//
+// 其中 code 为与 HTTP 状态码一致的 gRPC 状态码，details 为带有 @type 的错误详情，用于从 grpc-gateway 迁移时保持客户端兼容
+type GatewayWriter struct{}
+
+func (GatewayWriter) WriteResponse(c *gin.Context, status int, data any) {
+	c.JSON(status, data)
+}
+
+func (GatewayWriter) WriteError(c *gin.Context, err error) {
+	status, _, message := ErrorStatus(err)
+	code := gatewayCode(err, status)
+	details, _ := errorDetails(c, err)
+	if details == nil {
+		details = []json.RawMessage{}
+	}
+	c.JSON(status, gin.H{
+		"code":    int(code),
+		"message": message,
+		"details": details,
+	})
+}
+
+// gatewayCode 返回与 HTTP 状态码 status 一致的 gRPC 状态码：错误链中的 gRPC 状态码映射到相同的 HTTP 状态码时
+// 使用该状态码（如 400 的 FailedPrecondition），否则为 status 对应的状态码（如 413 的 ResourceExhausted）
+func gatewayCode(err error, status int) codes.Code {
+	if s, ok := grpcStatus(err); ok && HTTPStatusFromCode(s.Code()) == status {
+		return s.Code()
+	}
+	if code := perrors.CodeFromHTTPStatus(status); code != codes.OK {
+		return code
+	}
+	return codes.Unknown
+}
@@ -0,0 +1,305 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	perrors "github.com/JarrettGuo/protogin/errors"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// writeError 用 handler 写出 err，返回响应
+func writeError(t *testing.T, handler ErrorHandler, err error, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/v1/users/1", nil)
+	for k, v := range header {
+		c.Request.Header[k] = v
+	}
+	handler(c, err)
+	return w
+}
+
+// decodeBody 解码 JSON 响应体
+func decodeBody(t *testing.T, r io.Reader) map[string]any {
+	t.Helper()
+	data, _ := io.ReadAll(r)
+	var body map[string]any
+	if err := json.Unmarshal(data, &body); err != nil {
+		t.Fatalf("decode %q: %v", data, err)
+	}
+	return body
+}
+
+func TestWriteResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	data := map[string]any{"id": "1"}
+
+	tests := []struct {
+		writer ResponseWriter
+		want   map[string]any
+	}{
+		{EnvelopeWriter{}, map[string]any{"code": "SUCCESS", "message": "ok", "data": data, "success": true}},
+		{RawWriter{}, data},
+		{ProblemWriter{}, data},
+		{GatewayWriter{}, data},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		tt.writer.WriteResponse(c, http.StatusCreated, data)
+		if got := decodeBody(t, w.Body); w.Code != http.StatusCreated || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%T: %d %v, want 201 %v", tt.writer, w.Code, got, tt.want)
+		}
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	notFound := perrors.New("USER_NOT_FOUND", "user not found", http.StatusNotFound)
+
+	tests := []struct {
+		name   string
+		writer ResponseWriter
+		err    error
+		status int
+		want   map[string]any
+	}{
+		{
+			name: "envelope", writer: EnvelopeWriter{}, err: notFound, status: 404,
+			want: map[string]any{"code": "USER_NOT_FOUND", "message": "user not found", "success": false},
+		},
+		{
+			name: "raw", writer: RawWriter{}, err: notFound, status: 404,
+			want: map[string]any{"code": "USER_NOT_FOUND", "message": "user not found"},
+		},
+		{
+			name: "raw unclassified", writer: RawWriter{}, err: errors.New("db password wrong"), status: 500,
+			want: map[string]any{"code": "Internal", "message": "internal error"},
+		},
+		{
+			name: "problem", writer: ProblemWriter{}, err: notFound, status: 404,
+			want: map[string]any{
+				"type": "about:blank", "title": "Not Found", "status": float64(404), "detail": "user not found",
+				"instance": "/v1/users/1", "code": "USER_NOT_FOUND",
+			},
+		},
+		{
+			name: "problem with type base", writer: ProblemWriter{TypeBase: "https://example.com/errors/"}, err: notFound, status: 404,
+			want: map[string]any{
+				"type": "https://example.com/errors/USER_NOT_FOUND", "title": "Not Found", "status": float64(404), "detail": "user not found",
+				"instance": "/v1/users/1", "code": "USER_NOT_FOUND",
+			},
+		},
+		{
+			name: "gateway", writer: GatewayWriter{}, err: notFound, status: 404,
+			want: map[string]any{"code": float64(codes.NotFound), "message": "user not found", "details": []any{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := writeError(t, tt.writer.WriteError, tt.err, nil)
+			if got := decodeBody(t, w.Body); w.Code != tt.status || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%d %v, want %d %v", w.Code, got, tt.status, tt.want)
+			}
+		})
+	}
+
+	if ct := writeError(t, ProblemWriter{}.WriteError, notFound, nil).Header().Get("Content-Type"); ct != ContentTypeProblemJSON {
+		t.Errorf("problem Content-Type = %q", ct)
+	}
+}
+
+// TestWriteErrorDetails 错误详情按带有 @type 的 protojson 输出，BadRequest 的字段错误写入 fields，RetryInfo 写入 Retry-After
+func TestWriteErrorDetails(t *testing.T) {
+	st, _ := status.New(codes.InvalidArgument, "invalid user").WithDetails(
+		&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: "email", Description: "invalid email", Reason: "INVALID_EMAIL"},
+			{Field: "age", Description: "must be positive"},
+		}},
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(1500 * time.Millisecond)},
+	)
+	badRequest := map[string]any{
+		"@type": "type.googleapis.com/google.rpc.BadRequest",
+		"fieldViolations": []any{
+			map[string]any{"field": "email", "description": "invalid email", "reason": "INVALID_EMAIL"},
+			map[string]any{"field": "age", "description": "must be positive"},
+		},
+	}
+	retryInfo := map[string]any{"@type": "type.googleapis.com/google.rpc.RetryInfo", "retryDelay": "1.500s"}
+	fields := []any{
+		map[string]any{"field": "email", "description": "invalid email", "reason": "INVALID_EMAIL"},
+		map[string]any{"field": "age", "description": "must be positive"},
+	}
+
+	tests := []struct {
+		name   string
+		writer ResponseWriter
+		fields bool // 是否输出 fields
+	}{
+		{"envelope", EnvelopeWriter{}, true},
+		{"raw", RawWriter{}, true},
+		{"problem", ProblemWriter{}, true},
+		{"gateway", GatewayWriter{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := writeError(t, tt.writer.WriteError, st.Err(), nil)
+			body := decodeBody(t, w.Body)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status %d, want 400", w.Code)
+			}
+			if got := w.Header().Get("Retry-After"); got != "2" {
+				t.Errorf("Retry-After %q, want 2", got)
+			}
+			if want := []any{badRequest, retryInfo}; !reflect.DeepEqual(body["details"], want) {
+				t.Errorf("details %v, want %v", body["details"], want)
+			}
+			if got, ok := body["fields"]; tt.fields && !reflect.DeepEqual(got, fields) || !tt.fields && ok {
+				t.Errorf("fields %v", got)
+			}
+		})
+	}
+}
+
+func TestWriteErrorBusinessDetails(t *testing.T) {
+	err := perrors.New("QUOTA_EXCEEDED", "quota exceeded", http.StatusTooManyRequests).
+		WithDomain("example.com").
+		WithMetadata("limit", "100").
+		WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(-time.Second)})
+
+	w := writeError(t, RawWriter{}.WriteError, err, nil)
+	body := decodeBody(t, w.Body)
+	want := []any{
+		map[string]any{
+			"@type": "type.googleapis.com/google.rpc.ErrorInfo", "reason": "QUOTA_EXCEEDED",
+			"domain": "example.com", "metadata": map[string]any{"limit": "100"},
+		},
+		map[string]any{"@type": "type.googleapis.com/google.rpc.RetryInfo", "retryDelay": "-1s"},
+	}
+	if w.Code != http.StatusTooManyRequests || !reflect.DeepEqual(body["details"], want) {
+		t.Errorf("%d %v, want 429 %v", w.Code, body["details"], want)
+	}
+	// 负的重试间隔按 0 输出
+	if got := w.Header().Get("Retry-After"); got != "0" {
+		t.Errorf("Retry-After %q, want 0", got)
+	}
+
+	// 只有错误码的业务错误没有 ErrorInfo 详情
+	w = writeError(t, RawWriter{}.WriteError, perrors.New("QUOTA_EXCEEDED", "quota exceeded", http.StatusTooManyRequests), nil)
+	if body := decodeBody(t, w.Body); body["details"] != nil || w.Header().Get("Retry-After") != "" {
+		t.Errorf("details %v, Retry-After %q", body["details"], w.Header().Get("Retry-After"))
+	}
+}
+
+// TestWriteErrorUnknownDetail 类型未注册的详情输出 @type 和 base64 编码的 value
+func TestWriteErrorUnknownDetail(t *testing.T) {
+	st := status.FromProto(&spb.Status{
+		Code:    int32(codes.FailedPrecondition),
+		Message: "not ready",
+		Details: []*anypb.Any{{TypeUrl: "type.googleapis.com/example.Unknown", Value: []byte{1, 2}}},
+	})
+
+	w := writeError(t, RawWriter{}.WriteError, st.Err(), nil)
+	want := []any{map[string]any{"@type": "type.googleapis.com/example.Unknown", "value": "AQI="}}
+	if body := decodeBody(t, w.Body); !reflect.DeepEqual(body["details"], want) {
+		t.Errorf("details %v, want %v", body["details"], want)
+	}
+}
+
+// TestGatewayWriterCode code 与 HTTP 状态码始终一致
+func TestGatewayWriterCode(t *testing.T) {
+	// 后端返回的 413 业务错误，经 gRPC 传输后 ErrorInfo 中带有 HTTP 状态码
+	tooLarge := perrors.FromGRPCStatus(perrors.New("TOO_LARGE", "too large", http.StatusRequestEntityTooLarge).GRPCStatus())
+	teapot := perrors.New("TEAPOT", "teapot", http.StatusTeapot)
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   codes.Code
+	}{
+		{"business error", perrors.New("X", "x", http.StatusConflict), 409, codes.AlreadyExists},
+		{"business error restored from ErrorInfo", tooLarge, 413, codes.ResourceExhausted},
+		{"business error without exact code", teapot, 418, codes.FailedPrecondition},
+		{"bind error 413", &BindError{Status: http.StatusRequestEntityTooLarge, Message: "too large"}, 413, codes.ResourceExhausted},
+		{"bind error 415", &BindError{Status: http.StatusUnsupportedMediaType, Message: "bad type"}, 415, codes.InvalidArgument},
+		{"gRPC status", status.Error(codes.FailedPrecondition, "not ready"), 400, codes.FailedPrecondition},
+		{"wrapped gRPC status", fmt.Errorf("call: %w", status.Error(codes.DataLoss, "lost")), 500, codes.DataLoss},
+		{"gRPC ResourceExhausted", status.Error(codes.ResourceExhausted, "slow down"), 429, codes.ResourceExhausted},
+		{"deadline", fmt.Errorf("handler: %w", context.DeadlineExceeded), 504, codes.DeadlineExceeded},
+		{"canceled", context.Canceled, 499, codes.Canceled},
+		{"unclassified", errors.New("boom"), 500, codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := writeError(t, GatewayWriter{}.WriteError, tt.err, nil)
+			body := decodeBody(t, w.Body)
+			if w.Code != tt.status || body["code"] != float64(tt.code) {
+				t.Errorf("%d code %v, want %d code %d (%v)", w.Code, body["code"], tt.status, tt.code, tt.code)
+			}
+		})
+	}
+}
+
+func TestNegotiateErrorHandler(t *testing.T) {
+	handler := NegotiateErrorHandler(RawWriter{}.WriteError, map[string]ErrorHandler{
+		ContentTypeProblemJSON:          ProblemWriter{}.WriteError,
+		"application/grpc-gateway+json": GatewayWriter{}.WriteError,
+	})
+	err := perrors.New("USER_NOT_FOUND", "user not found", http.StatusNotFound)
+
+	tests := []struct {
+		accept string
+		want   string // 选中的格式：raw、problem 或 gateway
+	}{
+		{"", "raw"},
+		{"application/json", "raw"},
+		{"*/*", "raw"},
+		{"application/problem+json", "problem"},
+		{"application/problem+json, application/json;q=0.9", "problem"},
+		{"application/json, application/problem+json", "raw"},
+		{"application/json;q=0.5, application/problem+json;q=0.8", "problem"},
+		{"application/problem+json;q=0.5, application/json;q=0.8", "raw"},
+		{"application/*;q=0.2, application/problem+json", "problem"},
+		// 相同的 q 值时 application/json 优先
+		{"application/*", "raw"},
+		{"application/grpc-gateway+json, text/html", "gateway"},
+		// q 值相同的多个格式按媒体类型排序选择
+		{"application/problem+json, application/grpc-gateway+json, application/json;q=0.1", "gateway"},
+		{"text/html", "raw"},
+		{"application/problem+json;q=abc", "raw"},
+		{"not a media type, application/problem+json", "problem"},
+	}
+	for _, tt := range tests {
+		w := writeError(t, handler, err, http.Header{"Accept": {tt.accept}})
+		got := "raw"
+		switch body := decodeBody(t, w.Body); {
+		case body["type"] != nil:
+			got = "problem"
+		case body["details"] != nil:
+			got = "gateway"
+		}
+		if got != tt.want {
+			t.Errorf("Accept %q chose %s, want %s", tt.accept, got, tt.want)
+		}
+		if w.Header().Get("Vary") != "Accept" {
+			t.Errorf("Accept %q: Vary = %q", tt.accept, w.Header().Get("Vary"))
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package runtime
+
+import (
+	"fmt"
+	pathpkg "path"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// verbParam 最后一段为字面量的自定义动词路由使用的 gin 参数名，如 /v1/users:search 注册为 /v1/users:__verb
+const verbParam = "__verb"
+
+// routeKey 同一个 gin engine 上的 gin 路由，path 为包含分组前缀的绝对路由
+type routeKey struct {
+	method string
+	path   string
+}
+
+// dispatcher 在同一个 gin 路由上按自定义动词（:verb 后缀）分发请求
+type dispatcher struct {
+	mu           sync.RWMutex
+	param        string                     // 携带动词的 gin 参数名，最后一段不是参数时为空
+	handlers     map[string]gin.HandlerFunc // verb -> handler，空字符串表示不带动词的路由
+	errorHandler ErrorHandler               // 没有匹配的处理函数时返回 NotFound
+}
+
+// Routes 保存生成的路由向 gin 注册的 dispatcher，与 gin engine 一样由调用方持有
+//
+// gin 按 engine 和绝对路由保存处理函数，同一 engine 上方法和绝对路由相同、只有自定义动词不同的路由
+// 只能向 gin 注册一次。这些路由分属多个服务或多个分组时，注册时应通过 WithRoutes 使用同一个 Routes，
+// 否则 gin 会因为重复注册而 panic；未设置 WithRoutes 时每次注册服务使用各自的 Routes
+type Routes struct {
+	mu          sync.Mutex
+	dispatchers map[routeKey]*dispatcher
+}
+
+// NewRoutes 返回空的 Routes，一个 Routes 只用于一个 gin engine
+func NewRoutes() *Routes {
+	return &Routes{dispatchers: make(map[routeKey]*dispatcher)}
+}
+
+// Handle 在 router 上注册生成的路由，verb 为 google.api.http 模板中的自定义动词（不含冒号），没有动词时为空
+//
+// gin 的路由语法中 : 表示参数，无法直接表达 /v1/users/{id}:ban 这样的模板。
+// 所有路由都通过共享的 dispatcher 注册：同一方法和绝对路由只向 gin 注册一次，
+// 请求到达时从最后一个路径段中拆出 :verb 后缀，再分发到对应的处理函数；
+// 没有匹配的动词时以 NotFound 交给 errorHandler，错误处理器和 gin 处理链中的分组中间件都来自第一次注册该路由的调用。
+// 与 gin 一样，同一方法、绝对路由和动词重复注册时 panic
+func (rs *Routes) Handle(router gin.IRouter, method, path, verb string, handler gin.HandlerFunc, errorHandler ErrorHandler) {
+	param := lastParam(path)
+	if verb != "" && param == "" {
+		param = verbParam
+	}
+	path = RoutePath(path, verb)
+
+	key := routeKey{method: method, path: path}
+	if g := router.Group(""); g != nil {
+		key.path = joinPaths(g.BasePath(), path)
+	}
+	rs.mu.Lock()
+	d, ok := rs.dispatchers[key]
+	if !ok {
+		d = &dispatcher{param: param, handlers: make(map[string]gin.HandlerFunc), errorHandler: errorHandler}
+		rs.dispatchers[key] = d
+	}
+	rs.mu.Unlock()
+
+	d.mu.Lock()
+	_, exists := d.handlers[verb]
+	if !exists {
+		d.handlers[verb] = handler
+	}
+	d.mu.Unlock()
+	if exists {
+		panic(fmt.Sprintf("protogin: handler is already registered for %s %s", method, joinVerb(key.path, verb)))
+	}
+
+	if !ok {
+		router.Handle(method, path, d.serve)
+	}
+}
+
+// joinPaths 与 gin 计算分组内绝对路由的方式一致，保留末尾的 /
+func joinPaths(base, relative string) string {
+	if relative == "" {
+		return base
+	}
+	p := pathpkg.Join(base, relative)
+	if strings.HasSuffix(relative, "/") && !strings.HasSuffix(p, "/") {
+		return p + "/"
+	}
+	return p
+}
+
+// RoutePath 返回 Routes.Handle 实际向 gin 注册的路由
+//
+// 最后一段为字面量的自定义动词路由用参数匹配紧跟其后的 :verb，如 /v1/users:search 注册为 /v1/users:__verb
+func RoutePath(path, verb string) string {
+	if verb != "" && lastParam(path) == "" {
+		return path + ":" + verbParam
+	}
+	return path
+}
+
+// joinVerb 返回带有自定义动词的路由，用于错误信息
+func joinVerb(path, verb string) string {
+	if verb == "" {
+		return path
+	}
+	return strings.TrimSuffix(path, ":"+verbParam) + ":" + verb
+}
+
+func (d *dispatcher) serve(c *gin.Context) {
+	if d.param == "" {
+		d.dispatch(c, "")
+		return
+	}
+
+	value := c.Param(d.param)
+	if d.param == verbParam {
+		// /v1/users:search 中参数的值为 :search
+		if verb, ok := strings.CutPrefix(value, ":"); ok && verb != "" {
+			d.dispatch(c, verb)
+			return
+		}
+		d.notFound(c)
+		return
+	}
+
+	// 只在最后一个路径段中查找动词，catch-all 参数的值可能包含 /
+	seg := value[strings.LastIndexByte(value, '/')+1:]
+	if i := strings.LastIndexByte(seg, ':'); i > 0 && i < len(seg)-1 {
+		if h, ok := d.lookup(seg[i+1:]); ok {
+			setParam(c, d.param, value[:len(value)-len(seg)+i])
+			h(c)
+			return
+		}
+	}
+	// 没有匹配的动词时按普通路由处理，参数值中的冒号原样保留
+	d.dispatch(c, "")
+}
+
+func (d *dispatcher) lookup(verb string) (gin.HandlerFunc, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	h, ok := d.handlers[verb]
+	return h, ok
+}
+
+func (d *dispatcher) dispatch(c *gin.Context, verb string) {
+	if h, ok := d.lookup(verb); ok {
+		h(c)
+		return
+	}
+	d.notFound(c)
+}
+
+// notFound 与其他请求错误一样通过 ErrorHandler 返回 NotFound
+func (d *dispatcher) notFound(c *gin.Context) {
+	d.errorHandler(c, status.Errorf(codes.NotFound, "no handler for %s %s", c.Request.Method, c.Request.URL.Path))
+	c.Abort()
+}
+
+// lastParam 返回 gin 路由最后一段的参数名，最后一段不是参数时返回空
+func lastParam(path string) string {
+	seg := path[strings.LastIndexByte(path, '/')+1:]
+	if len(seg) > 1 && (seg[0] == ':' || seg[0] == '*') {
+		return seg[1:]
+	}
+	return ""
+}
+
+// setParam 修改 gin 路由参数的值
+func setParam(c *gin.Context, name, value string) {
+	for i := range c.Params {
+		if c.Params[i].Key == name {
+			c.Params[i].Value = value
+			return
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package runtime
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestLastParam(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/v1/users/:id", "id"},
+		{"/v1/shelves/*name", "name"},
+		{"/v1/users", ""},
+		{"/v1/users/:id/books", ""},
+		{"/v1/:", ""},
+		{"/", ""},
+	}
+	for _, tt := range tests {
+		if got := lastParam(tt.path); got != tt.want {
+			t.Errorf("lastParam(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestRoutePath(t *testing.T) {
+	tests := []struct {
+		path, verb string
+		want       string
+	}{
+		{"/v1/users", "", "/v1/users"},
+		{"/v1/users", "search", "/v1/users:__verb"},
+		{"/v1/users/:id", "ban", "/v1/users/:id"},
+		{"/v1/shelves/*name", "move", "/v1/shelves/*name"},
+		{"/v1/users/:id/books", "list", "/v1/users/:id/books:__verb"},
+	}
+	for _, tt := range tests {
+		if got := RoutePath(tt.path, tt.verb); got != tt.want {
+			t.Errorf("RoutePath(%q, %q) = %q, want %q", tt.path, tt.verb, got, tt.want)
+		}
+	}
+}
+
+func TestJoinVerb(t *testing.T) {
+	tests := []struct {
+		path, verb string
+		want       string
+	}{
+		{"/v1/users", "", "/v1/users"},
+		{"/v1/users:__verb", "search", "/v1/users:search"},
+		{"/v1/users/:id", "ban", "/v1/users/:id:ban"},
+	}
+	for _, tt := range tests {
+		if got := joinVerb(tt.path, tt.verb); got != tt.want {
+			t.Errorf("joinVerb(%q, %q) = %q, want %q", tt.path, tt.verb, got, tt.want)
+		}
+	}
+}
+
+func TestJoinPaths(t *testing.T) {
+	tests := []struct {
+		base, relative string
+		want           string
+	}{
+		{"/", "/v1/users", "/v1/users"},
+		{"/api", "/v1/users:__verb", "/api/v1/users:__verb"},
+		{"/api/", "v1/users/", "/api/v1/users/"},
+		{"/api", "", "/api"},
+	}
+	for _, tt := range tests {
+		if got := joinPaths(tt.base, tt.relative); got != tt.want {
+			t.Errorf("joinPaths(%q, %q) = %q, want %q", tt.base, tt.relative, got, tt.want)
+		}
+	}
+}
+
+// named 返回写出 name 和路由参数的处理函数，用于区分请求分发到了哪个处理函数
+func named(name string, params ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		out := name
+		for _, p := range params {
+			out += " " + p + "=" + c.Param(p)
+		}
+		c.String(http.StatusOK, out)
+	}
+}
+
+// newHandle 返回在同一个 Routes 上注册路由的函数，错误按 RawWriter 输出
+func newHandle() func(router gin.IRouter, method, path, verb string, handler gin.HandlerFunc) {
+	rs := NewRoutes()
+	return func(router gin.IRouter, method, path, verb string, handler gin.HandlerFunc) {
+		rs.Handle(router, method, path, verb, handler, RawWriter{}.WriteError)
+	}
+}
+
+func serve(t *testing.T, r http.Handler, method, path string) (int, string) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+	return w.Code, w.Body.String()
+}
+
+func TestHandleDispatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	handle := newHandle()
+	handle(r, http.MethodGet, "/v1/users/:id", "", named("get", "id"))
+	handle(r, http.MethodPost, "/v1/users/:id", "ban", named("ban", "id"))
+	handle(r, http.MethodPost, "/v1/users/:id", "mute", named("mute", "id"))
+	handle(r, http.MethodGet, "/v1/users", "search", named("search"))
+	handle(r, http.MethodGet, "/v1/users", "export", named("export"))
+	handle(r, http.MethodGet, "/v1/shelves/*name", "", named("shelf", "name"))
+	handle(r, http.MethodPost, "/v1/shelves/*name", "move", named("move", "name"))
+
+	tests := []struct {
+		method, path string
+		code         int
+		body         string
+	}{
+		{http.MethodGet, "/v1/users/42", 200, "get id=42"},
+		{http.MethodPost, "/v1/users/42:ban", 200, "ban id=42"},
+		{http.MethodPost, "/v1/users/42:mute", 200, "mute id=42"},
+		// 没有不带动词的 POST 路由
+		{http.MethodPost, "/v1/users/42", 404, ""},
+		{http.MethodPost, "/v1/users/42:kick", 404, ""},
+		// 不匹配的动词按普通路由处理，冒号保留在参数中
+		{http.MethodGet, "/v1/users/a:b", 200, "get id=a:b"},
+		{http.MethodGet, "/v1/users:search", 200, "search"},
+		{http.MethodGet, "/v1/users:export", 200, "export"},
+		{http.MethodGet, "/v1/users:unknown", 404, ""},
+		{http.MethodGet, "/v1/users:", 404, ""},
+		{http.MethodGet, "/v1/shelves/a/b/c", 200, "shelf name=/a/b/c"},
+		{http.MethodPost, "/v1/shelves/a/b:move", 200, "move name=/a/b"},
+		// 只有最后一段中的冒号是动词
+		{http.MethodGet, "/v1/shelves/a:move/b", 200, "shelf name=/a:move/b"},
+		{http.MethodPost, "/v1/shelves/a:move/b", 404, ""},
+	}
+	for _, tt := range tests {
+		code, body := serve(t, r, tt.method, tt.path)
+		if tt.code == http.StatusNotFound {
+			tt.body = `{"code":"NotFound","message":"no handler for ` + tt.method + " " + tt.path + `"}`
+		}
+		if code != tt.code || body != tt.body {
+			t.Errorf("%s %s = %d %q, want %d %q", tt.method, tt.path, code, body, tt.code, tt.body)
+		}
+	}
+}
+
+func TestHandleSharedRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("groups with the same prefix", func(t *testing.T) {
+		r := gin.New()
+		handle := newHandle()
+		handle(r.Group("/api"), http.MethodPost, "/users", "search", named("search"))
+		handle(r.Group("/api"), http.MethodPost, "/users", "batch", named("batch"))
+
+		for verb, want := range map[string]string{"search": "search", "batch": "batch"} {
+			if code, body := serve(t, r, http.MethodPost, "/api/users:"+verb); code != 200 || body != want {
+				t.Errorf("POST /api/users:%s = %d %q, want 200 %q", verb, code, body, want)
+			}
+		}
+	})
+
+	t.Run("group and engine", func(t *testing.T) {
+		r := gin.New()
+		handle := newHandle()
+		handle(r.Group("/api"), http.MethodPost, "/users/:id", "ban", named("ban", "id"))
+		handle(r, http.MethodPost, "/api/users/:id", "mute", named("mute", "id"))
+
+		if code, body := serve(t, r, http.MethodPost, "/api/users/7:ban"); code != 200 || body != "ban id=7" {
+			t.Errorf("ban = %d %q", code, body)
+		}
+		if code, body := serve(t, r, http.MethodPost, "/api/users/7:mute"); code != 200 || body != "mute id=7" {
+			t.Errorf("mute = %d %q", code, body)
+		}
+	})
+
+	t.Run("separate engines", func(t *testing.T) {
+		r1, r2 := gin.New(), gin.New()
+		newHandle()(r1, http.MethodGet, "/users", "search", named("one"))
+		newHandle()(r2, http.MethodGet, "/users", "search", named("two"))
+
+		if _, body := serve(t, r1, http.MethodGet, "/users:search"); body != "one" {
+			t.Errorf("r1 = %q, want one", body)
+		}
+		if _, body := serve(t, r2, http.MethodGet, "/users:search"); body != "two" {
+			t.Errorf("r2 = %q, want two", body)
+		}
+	})
+}
+
+func TestHandleDuplicatePanics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		register func(r *gin.Engine)
+		want     string
+	}{
+		{
+			name: "same router",
+			register: func(r *gin.Engine) {
+				handle := newHandle()
+				handle(r, http.MethodPost, "/users/:id", "ban", named("a"))
+				handle(r, http.MethodPost, "/users/:id", "ban", named("b"))
+			},
+			want: "POST /users/:id:ban",
+		},
+		{
+			name: "groups with the same prefix",
+			register: func(r *gin.Engine) {
+				handle := newHandle()
+				handle(r.Group("/api"), http.MethodGet, "/users", "search", named("a"))
+				handle(r.Group("/api"), http.MethodGet, "/users", "search", named("b"))
+			},
+			want: "GET /api/users:search",
+		},
+		{
+			name: "group and engine without verb",
+			register: func(r *gin.Engine) {
+				handle := newHandle()
+				handle(r.Group("/api"), http.MethodGet, "/users", "", named("a"))
+				handle(r, http.MethodGet, "/api/users", "", named("b"))
+			},
+			want: "GET /api/users",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				r := recover()
+				msg, _ := r.(string)
+				if !strings.Contains(msg, "already registered") || !strings.Contains(msg, tt.want) {
+					t.Fatalf("panic = %v, want already registered for %s", r, tt.want)
+				}
+			}()
+			tt.register(gin.New())
+		})
+	}
+}
+
+// TestHandleSeparateRoutes 同一 engine 上的共用路由不通过同一个 Routes 注册时，由 gin 报告重复注册
+func TestHandleSeparateRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	newHandle()(r, http.MethodGet, "/users", "search", named("search"))
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("registering the shared route with another Routes did not panic")
+		}
+	}()
+	newHandle()(r, http.MethodGet, "/users", "export", named("export"))
+}
+
+// TestRoutesErrorHandler 没有匹配的动词时使用第一次注册该路由时的错误处理器
+func TestRoutesErrorHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	rs := NewRoutes()
+	rs.Handle(r, http.MethodGet, "/users", "search", named("search"), ProblemWriter{}.WriteError)
+	rs.Handle(r, http.MethodGet, "/users", "export", named("export"), RawWriter{}.WriteError)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users:unknown", nil))
+	if w.Code != http.StatusNotFound || w.Header().Get("Content-Type") != ContentTypeProblemJSON {
+		t.Errorf("%d %s %s, want 404 %s", w.Code, w.Header().Get("Content-Type"), w.Body, ContentTypeProblemJSON)
+	}
+}
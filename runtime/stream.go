@@ -0,0 +1,228 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/genproto/googleapis/api/httpbody"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// MetadataHeaderPrefix 流式方法通过 SetHeader/SendHeader 设置的 gRPC metadata 对应的 HTTP 响应头前缀
+const MetadataHeaderPrefix = "Grpc-Metadata-"
+
+// ServerStream 把 gin 请求适配为 grpc.ServerStream
+//
+// 发送的每条消息按请求的 Accept 编码后立即 flush：
+//   - text/event-stream 的 q 值高于 application/x-ndjson 时：SSE 事件 data: {...}，错误为 event: error
+//   - 其他（包括没有 Accept）：NDJSON（application/x-ndjson），每行为 {"result": {...}}，错误为 {"error": {...}}
+//
+// 消息为 google.api.HttpBody 时按第一条消息的 content_type 原样写入各条消息的 data（如导出 CSV），
+// 开始发送后的错误通过 HTTP trailer Grpc-Status 和 Grpc-Message 返回
+//
+// 客户端断开连接后 context 被取消，SendMsg 返回错误，服务实现应当结束流
+type ServerStream struct {
+	c    *gin.Context
+	ctx  context.Context
+	opts *ServerOptions
+
+	sse     bool
+	raw     bool // 消息为 google.api.HttpBody
+	header  metadata.MD
+	started bool // 是否已经写入响应头
+
+	// Redact 为 true 时发送前清除 (protogin.field).redact 字段，由生成的代码设置
+	Redact bool
+}
+
+func newServerStream(c *gin.Context, ctx context.Context, opts *ServerOptions) *ServerStream {
+	accept := parseAccept(c.GetHeader("Accept"))
+	return &ServerStream{
+		c:      c,
+		ctx:    ctx,
+		opts:   opts,
+		sse:    quality(accept, "text/event-stream") > quality(accept, "application/x-ndjson"),
+		header: metadata.MD{},
+	}
+}
+
+// Context 返回请求的 context，客户端断开连接或超时后被取消
+func (s *ServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// SetHeader 设置响应头，在发送第一条消息时写入
+func (s *ServerStream) SetHeader(md metadata.MD) error {
+	if s.started {
+		return status.Error(codes.Internal, "SetHeader called after headers were sent")
+	}
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+// SendHeader 立即写入响应头
+func (s *ServerStream) SendHeader(md metadata.MD) error {
+	if err := s.SetHeader(md); err != nil {
+		return err
+	}
+	s.writeHeader()
+	return nil
+}
+
+// SetTrailer HTTP 响应不支持 trailer，忽略
+func (s *ServerStream) SetTrailer(metadata.MD) {}
+
+// SendMsg 编码并发送一条消息
+func (s *ServerStream) SendMsg(m any) error {
+	if err := s.ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "stream message %T is not a proto message", m)
+	}
+	if body, ok := msg.(*httpbody.HttpBody); ok {
+		if !s.started {
+			s.raw = true
+			setHTTPBodyHeader(s.c.Writer.Header(), body)
+		}
+		return s.write(body.GetData())
+	}
+	if s.Redact {
+		msg = Redact(msg)
+	}
+
+	data, err := s.opts.Marshaler.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	var frame bytes.Buffer
+	if s.sse {
+		frame.WriteString("data: ")
+		frame.Write(bytes.ReplaceAll(data, []byte("\n"), []byte("\ndata: ")))
+		frame.WriteString("\n\n")
+	} else {
+		frame.WriteString(`{"result":`)
+		frame.Write(data)
+		frame.WriteString("}\n")
+	}
+	return s.write(frame.Bytes())
+}
+
+// RecvMsg 服务端流式方法的请求消息已经解码，不支持再读取
+func (s *ServerStream) RecvMsg(any) error {
+	return status.Error(codes.Unimplemented, "RecvMsg is not supported on server streams")
+}
+
+// Finish 结束流，err 为服务实现的返回值
+//
+// 还没有发送任何消息时，错误按普通请求交给 ErrorHandler；已经开始发送后，错误编码为最后一帧。
+// 方法的超时（(protogin.method).timeout）到期导致的错误按 codes.DeadlineExceeded 返回，客户端断开后不再写入
+func (s *ServerStream) Finish(err error) {
+	if err == nil {
+		s.writeHeader()
+		return
+	}
+	if ctxErr := s.ctx.Err(); ctxErr != nil && errors.Is(err, ctxErr) {
+		err = status.FromContextError(ctxErr).Err()
+	}
+	if !s.started {
+		s.opts.ErrorHandler(s.c, err)
+		return
+	}
+	if s.c.Request.Context().Err() != nil {
+		// 客户端已经断开
+		return
+	}
+	if s.raw {
+		_, _, message := ErrorStatus(err)
+		h := s.c.Writer.Header()
+		h.Set(http.TrailerPrefix+"Grpc-Status", strconv.Itoa(int(status.Code(err))))
+		h.Set(http.TrailerPrefix+"Grpc-Message", message)
+		return
+	}
+
+	_, code, message := ErrorStatus(err)
+	data, _ := json.Marshal(gin.H{"code": code, "message": message})
+
+	var frame bytes.Buffer
+	if s.sse {
+		frame.WriteString("event: error\ndata: ")
+		frame.Write(data)
+		frame.WriteString("\n\n")
+	} else {
+		frame.WriteString(`{"error":`)
+		frame.Write(data)
+		frame.WriteString("}\n")
+	}
+	_ = s.write(frame.Bytes())
+}
+
+func (s *ServerStream) writeHeader() {
+	if s.started {
+		return
+	}
+	s.started = true
+
+	h := s.c.Writer.Header()
+	setMetadataHeader(h, s.header)
+	switch {
+	case s.raw:
+		if h.Get("Content-Type") == "" {
+			h.Set("Content-Type", "application/octet-stream")
+		}
+	case s.sse:
+		h.Set("Content-Type", "text/event-stream")
+		h.Set("X-Accel-Buffering", "no")
+		h.Add("Vary", "Accept")
+	default:
+		h.Set("Content-Type", "application/x-ndjson")
+		h.Add("Vary", "Accept")
+	}
+	h.Set("Cache-Control", "no-cache")
+	s.c.Status(http.StatusOK)
+	s.c.Writer.WriteHeaderNow()
+	s.c.Writer.Flush()
+}
+
+func (s *ServerStream) write(frame []byte) error {
+	s.writeHeader()
+	if _, err := s.c.Writer.Write(frame); err != nil {
+		return status.Errorf(codes.Unavailable, "write stream: %v", err)
+	}
+	s.c.Writer.Flush()
+	return nil
+}
+
+// ServerStreamingServer 实现 grpc.ServerStreamingServer[T]，用于服务端流式方法
+type ServerStreamingServer[T any] struct {
+	*ServerStream
+}
+
+// NewServerStreamingServer 由生成的处理函数调用，ctx 为请求的 context（可能带有超时）
+func NewServerStreamingServer[T any](c *gin.Context, ctx context.Context, opts *ServerOptions) *ServerStreamingServer[T] {
+	return &ServerStreamingServer[T]{ServerStream: newServerStream(c, ctx, opts)}
+}
+
+// Send 发送一条响应消息
+func (s *ServerStreamingServer[T]) Send(m *T) error {
+	return s.SendMsg(m)
+}
+
+// setMetadataHeader 把 gRPC metadata 以 MetadataHeaderPrefix 前缀写入响应头
+func setMetadataHeader(h http.Header, md metadata.MD) {
+	for k, vs := range md {
+		for _, v := range vs {
+			h.Add(MetadataHeaderPrefix+k, v)
+		}
+	}
+}
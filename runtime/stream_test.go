@@ -0,0 +1,197 @@
+package runtime
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type testStreamServer = ServerStreamingServer[wrapperspb.StringValue]
+
+// newStreamServer 启动以 handler 为服务端流式方法实现的测试服务器，timeout 与生成代码的 (protogin.method).timeout 相同，0 表示不限制
+func newStreamServer(t *testing.T, timeout time.Duration, handler func(*testStreamServer) error) string {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	o := NewServerOptions()
+
+	r := gin.New()
+	r.GET("/watch", func(c *gin.Context) {
+		ctx := c.Request.Context()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		s := NewServerStreamingServer[wrapperspb.StringValue](c, ctx, o)
+		s.Finish(handler(s))
+	})
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return srv.URL + "/watch"
+}
+
+func get(t *testing.T, url, accept string) *http.Response {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// send 依次发送 values，然后返回 err
+func send(err error, values ...string) func(*testStreamServer) error {
+	return func(s *testStreamServer) error {
+		for _, v := range values {
+			if err := s.Send(wrapperspb.String(v)); err != nil {
+				return err
+			}
+		}
+		return err
+	}
+}
+
+func TestServerStreamFraming(t *testing.T) {
+	const (
+		ndjson = `{"result":"a"}` + "\n" + `{"result":"b"}` + "\n"
+		sse    = "data: \"a\"\n\ndata: \"b\"\n\n"
+	)
+	aborted := status.Error(codes.Aborted, "boom")
+
+	tests := []struct {
+		name        string
+		accept      string
+		err         error
+		contentType string
+		body        string
+	}{
+		{"no Accept", "", nil, "application/x-ndjson", ndjson},
+		{"any", "*/*", nil, "application/x-ndjson", ndjson},
+		{"SSE", "text/event-stream", nil, "text/event-stream", sse},
+		{"SSE preferred", "application/x-ndjson;q=0.5, text/event-stream", nil, "text/event-stream", sse},
+		{"SSE not acceptable", "text/event-stream;q=0", nil, "application/x-ndjson", ndjson},
+		{"NDJSON preferred", "application/x-ndjson, text/event-stream;q=0.5", nil, "application/x-ndjson", ndjson},
+		{"NDJSON error", "", aborted, "application/x-ndjson", ndjson + `{"error":{"code":"Aborted","message":"boom"}}` + "\n"},
+		{"SSE error", "text/event-stream", aborted, "text/event-stream", sse + "event: error\ndata: {\"code\":\"Aborted\",\"message\":\"boom\"}\n\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := get(t, newStreamServer(t, 0, send(tt.err, "a", "b")), tt.accept)
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != tt.contentType {
+				t.Errorf("%d %s, want 200 %s", resp.StatusCode, resp.Header.Get("Content-Type"), tt.contentType)
+			}
+			if string(body) != tt.body {
+				t.Errorf("body %q, want %q", body, tt.body)
+			}
+			if resp.Header.Get("Vary") != "Accept" || resp.Header.Get("Cache-Control") != "no-cache" {
+				t.Errorf("Vary %q, Cache-Control %q", resp.Header.Get("Vary"), resp.Header.Get("Cache-Control"))
+			}
+		})
+	}
+}
+
+// TestServerStreamErrorBeforeSend 没有发送消息时错误按普通请求返回
+func TestServerStreamErrorBeforeSend(t *testing.T) {
+	resp := get(t, newStreamServer(t, 0, send(status.Error(codes.NotFound, "no such user"))), "text/event-stream")
+	body := decodeBody(t, resp.Body)
+	if resp.StatusCode != http.StatusNotFound || body["code"] != "NotFound" {
+		t.Errorf("%d %v, want 404 NotFound", resp.StatusCode, body)
+	}
+}
+
+// TestServerStreamFlush 每条消息发送后立即到达客户端，不等待流结束
+func TestServerStreamFlush(t *testing.T) {
+	next := make(chan struct{})
+	url := newStreamServer(t, 0, func(s *testStreamServer) error {
+		for _, v := range []string{"a", "b"} {
+			if err := s.Send(wrapperspb.String(v)); err != nil {
+				return err
+			}
+			select {
+			case <-next:
+			case <-time.After(5 * time.Second):
+				return status.Error(codes.DeadlineExceeded, "client did not read the message")
+			}
+		}
+		return nil
+	})
+
+	lines := bufio.NewReader(get(t, url, "").Body)
+	for _, want := range []string{`{"result":"a"}`, `{"result":"b"}`} {
+		line, err := lines.ReadString('\n')
+		if err != nil || line != want+"\n" {
+			t.Fatalf("read %q, %v; want %q", line, err, want)
+		}
+		next <- struct{}{}
+	}
+}
+
+// TestServerStreamClientDisconnect 客户端断开后 context 被取消，Send 返回错误
+func TestServerStreamClientDisconnect(t *testing.T) {
+	done := make(chan error, 1)
+	url := newStreamServer(t, 0, func(s *testStreamServer) error {
+		for {
+			if err := s.Send(wrapperspb.String("tick")); err != nil {
+				done <- err
+				return err
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+
+	resp := get(t, url, "")
+	if _, err := bufio.NewReader(resp.Body).ReadString('\n'); err != nil {
+		t.Fatalf("read first message: %v", err)
+	}
+	resp.Body.Close()
+
+	select {
+	case err := <-done:
+		if code := status.Code(err); code != codes.Canceled && code != codes.Unavailable {
+			t.Errorf("Send error %v, want Canceled or Unavailable", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Send did not fail after the client disconnected")
+	}
+}
+
+// TestServerStreamTimeout 方法超时到期后按 DeadlineExceeded 结束流
+func TestServerStreamTimeout(t *testing.T) {
+	waitDeadline := func(values ...string) func(*testStreamServer) error {
+		return func(s *testStreamServer) error {
+			if err := send(nil, values...)(s); err != nil {
+				return err
+			}
+			<-s.Context().Done()
+			return s.Context().Err()
+		}
+	}
+
+	resp := get(t, newStreamServer(t, 50*time.Millisecond, waitDeadline("a")), "")
+	body, _ := io.ReadAll(resp.Body)
+	want := `{"result":"a"}` + "\n" + `{"error":{"code":"DeadlineExceeded","message":"context deadline exceeded"}}` + "\n"
+	if string(body) != want {
+		t.Errorf("body %q, want %q", body, want)
+	}
+
+	// 还没有发送消息时按普通请求返回 504
+	resp = get(t, newStreamServer(t, 50*time.Millisecond, waitDeadline()), "")
+	if body := decodeBody(t, resp.Body); resp.StatusCode != http.StatusGatewayTimeout || body["code"] != "DeadlineExceeded" {
+		t.Errorf("%d %v, want 504 DeadlineExceeded", resp.StatusCode, body)
+	}
+}
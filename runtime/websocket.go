@@ -0,0 +1,345 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// CloseCodeBase 双向流以错误结束时的 WebSocket 关闭码为 CloseCodeBase + gRPC 状态码，如 4005 表示 NotFound，
+// 关闭原因为错误信息。正常结束时关闭码为 1000
+const CloseCodeBase = 4000
+
+// DefaultPingInterval WebSocket 连接的默认 ping 间隔，超过两个间隔没有收到 pong 时认为连接已断开
+const DefaultPingInterval = 30 * time.Second
+
+// writeWait 写入单条消息或控制帧的超时时间
+const writeWait = 10 * time.Second
+
+// BidiStream 把 WebSocket 连接适配为 grpc.ServerStream，用于双向流式方法
+//
+// 文本帧按 protojson 编解码，二进制帧按 proto 二进制编解码；服务端发送的消息使用客户端最近一次发送的帧类型，
+// 客户端还没有发送消息时使用文本帧。客户端发送关闭帧后 RecvMsg 返回 io.EOF，连接断开或 keepalive 超时后 context 被取消。
+//
+// 连接在第一次 SendMsg、RecvMsg 或 SendHeader 时升级，在此之前返回的错误按普通请求交给 ErrorHandler
+type BidiStream struct {
+	c      *gin.Context
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	opts   *ServerOptions
+	header metadata.MD
+
+	once    sync.Once
+	started bool
+	conn    *websocket.Conn
+	err     error // 升级失败的错误，Upgrader 已经写入 HTTP 错误响应
+
+	frames  chan wsFrame
+	closed  chan struct{} // 读取协程退出后关闭
+	readErr error         // 读取协程退出的原因，closed 关闭后可读
+	binary  atomic.Bool   // 客户端最近一次发送的是否为二进制帧
+	n       int           // 已读取的消息数
+
+	// Redact 为 true 时发送前清除 (protogin.field).redact 字段，由生成的代码设置
+	Redact bool
+}
+
+type wsFrame struct {
+	typ  int
+	data []byte
+}
+
+func newBidiStream(c *gin.Context, ctx context.Context, opts *ServerOptions) *BidiStream {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &BidiStream{
+		c:      c,
+		ctx:    ctx,
+		cancel: cancel,
+		opts:   opts,
+		header: metadata.MD{},
+		frames: make(chan wsFrame),
+		closed: make(chan struct{}),
+	}
+}
+
+// Context 返回流的 context，客户端断开连接、keepalive 超时或方法超时后被取消
+func (s *BidiStream) Context() context.Context {
+	return s.ctx
+}
+
+// SetHeader 设置升级响应的响应头
+func (s *BidiStream) SetHeader(md metadata.MD) error {
+	if s.started {
+		return status.Error(codes.Internal, "SetHeader called after headers were sent")
+	}
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+// SendHeader 立即升级连接
+func (s *BidiStream) SendHeader(md metadata.MD) error {
+	if err := s.SetHeader(md); err != nil {
+		return err
+	}
+	s.upgrade()
+	return s.err
+}
+
+// SetTrailer WebSocket 不支持 trailer，忽略
+func (s *BidiStream) SetTrailer(metadata.MD) {}
+
+// SendMsg 编码并发送一条消息
+func (s *BidiStream) SendMsg(m any) error {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "stream message %T is not a proto message", m)
+	}
+	if s.upgrade(); s.err != nil {
+		return s.err
+	}
+	if err := s.ctxErr(); err != nil {
+		return err
+	}
+	if s.Redact {
+		msg = Redact(msg)
+	}
+
+	var (
+		typ  = websocket.TextMessage
+		data []byte
+		err  error
+	)
+	if s.binary.Load() {
+		typ = websocket.BinaryMessage
+		data, err = proto.Marshal(msg)
+		if err != nil {
+			err = status.Errorf(codes.Internal, "marshal response: %v", err)
+		}
+	} else {
+		data, err = s.opts.Marshaler.Marshal(msg)
+	}
+	if err != nil {
+		return err
+	}
+
+	_ = s.conn.SetWriteDeadline(time.Now().Add(writeWait))
+	if err := s.conn.WriteMessage(typ, data); err != nil {
+		err = status.Errorf(codes.Unavailable, "write websocket message: %v", err)
+		s.cancel(err)
+		return err
+	}
+	return nil
+}
+
+// RecvMsg 读取并解码下一条消息，客户端发送关闭帧后返回 io.EOF
+func (s *BidiStream) RecvMsg(m any) error {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "stream message %T is not a proto message", m)
+	}
+	if s.upgrade(); s.err != nil {
+		return s.err
+	}
+
+	select {
+	case f := <-s.frames:
+		s.binary.Store(f.typ == websocket.BinaryMessage)
+		var err error
+		if f.typ == websocket.BinaryMessage {
+			err = proto.Unmarshal(f.data, msg)
+		} else {
+			err = s.opts.BodyDecoder.UnmarshalOptions.Unmarshal(f.data, msg)
+		}
+		if err != nil {
+			return invalidBody(fmt.Sprintf("message[%d]", s.n), err, nil)
+		}
+		s.n++
+		return nil
+	case <-s.closed:
+		return s.readErr
+	case <-s.ctx.Done():
+		return s.ctxErr()
+	}
+}
+
+// Finish 结束流，err 为服务实现的返回值
+//
+// 连接还没有升级时，错误按普通请求交给 ErrorHandler；否则发送关闭帧，关闭码见 CloseCodeBase
+func (s *BidiStream) Finish(err error) {
+	if err != nil {
+		first := false
+		s.once.Do(func() { first = true })
+		if first {
+			s.cancel(context.Canceled)
+			s.opts.ErrorHandler(s.c, err)
+			return
+		}
+	}
+	if s.upgrade(); s.err != nil {
+		s.cancel(s.err)
+		return
+	}
+
+	code, reason := websocket.CloseNormalClosure, ""
+	if err != nil {
+		_, _, message := ErrorStatus(err)
+		code, reason = CloseCodeBase+int(status.Code(err)), closeReason(message)
+	}
+	_ = s.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(writeWait))
+	s.cancel(context.Canceled)
+
+	// 等待客户端回复关闭帧
+	select {
+	case <-s.closed:
+	case <-time.After(writeWait):
+	}
+	_ = s.conn.Close()
+}
+
+func (s *BidiStream) upgrade() {
+	s.once.Do(func() {
+		s.started = true
+
+		h := http.Header{}
+		setMetadataHeader(h, s.header)
+		conn, err := s.opts.Upgrader.Upgrade(s.c.Writer, s.c.Request, h)
+		if err != nil {
+			s.err = status.Errorf(codes.FailedPrecondition, "websocket upgrade: %v", err)
+			return
+		}
+		s.conn = conn
+
+		conn.SetReadLimit(int64(s.opts.MaxMessageSize))
+		// 关闭帧在 Finish 中携带状态回复，这里不自动回复
+		conn.SetCloseHandler(func(int, string) error { return nil })
+		if s.opts.PingInterval > 0 {
+			conn.SetPongHandler(func(string) error {
+				return conn.SetReadDeadline(time.Now().Add(2 * s.opts.PingInterval))
+			})
+			go s.keepalive()
+		}
+		go s.readLoop()
+	})
+}
+
+// readLoop 持续读取消息以便及时处理 pong 和关闭帧，读取到的消息交给 RecvMsg
+func (s *BidiStream) readLoop() {
+	defer close(s.closed)
+	for {
+		if s.opts.PingInterval > 0 {
+			_ = s.conn.SetReadDeadline(time.Now().Add(2 * s.opts.PingInterval))
+		}
+		typ, data, err := s.conn.ReadMessage()
+		if err != nil {
+			s.readErr = s.readError(err)
+			if s.readErr != io.EOF {
+				s.cancel(s.readErr)
+			}
+			return
+		}
+
+		select {
+		case s.frames <- wsFrame{typ: typ, data: data}:
+		case <-s.ctx.Done():
+			s.readErr = s.ctxErr()
+			return
+		}
+	}
+}
+
+// readError 把读取错误转换为 gRPC 错误，客户端正常关闭时返回 io.EOF
+func (s *BidiStream) readError(err error) error {
+	var ne net.Error
+	switch {
+	case websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway):
+		return io.EOF
+	case errors.Is(err, websocket.ErrReadLimit):
+		return status.Errorf(codes.ResourceExhausted, "message exceeds %d bytes", s.opts.MaxMessageSize)
+	case s.ctx.Err() != nil:
+		return s.ctxErr()
+	case errors.As(err, &ne) && ne.Timeout():
+		return status.Error(codes.Unavailable, "websocket keepalive timeout")
+	}
+	return status.Errorf(codes.Canceled, "websocket closed: %v", err)
+}
+
+// keepalive 定期发送 ping，发送失败时取消 context
+func (s *BidiStream) keepalive() {
+	ticker := time.NewTicker(s.opts.PingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-s.closed:
+			return
+		case <-ticker.C:
+			if err := s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				s.cancel(status.Errorf(codes.Unavailable, "websocket ping: %v", err))
+				return
+			}
+		}
+	}
+}
+
+// ctxErr 返回 context 被取消的原因，没有取消时返回 nil
+func (s *BidiStream) ctxErr() error {
+	if s.ctx.Err() == nil {
+		return nil
+	}
+	cause := context.Cause(s.ctx)
+	if _, ok := status.FromError(cause); ok {
+		return cause
+	}
+	return status.FromContextError(cause).Err()
+}
+
+// closeReason 关闭原因最多 123 字节（控制帧最多 125 字节，其中 2 字节为关闭码），按 UTF-8 字符截断
+func closeReason(message string) string {
+	n := 123
+	if len(message) <= n {
+		return message
+	}
+	for n > 0 && !utf8.RuneStart(message[n]) {
+		n--
+	}
+	return message[:n]
+}
+
+// BidiStreamingServer 实现 grpc.BidiStreamingServer[Req, Res]，用于双向流式方法
+type BidiStreamingServer[Req, Res any] struct {
+	*BidiStream
+}
+
+// NewBidiStreamingServer 由生成的处理函数调用，ctx 为请求的 context（可能带有超时）
+func NewBidiStreamingServer[Req, Res any](c *gin.Context, ctx context.Context, opts *ServerOptions) *BidiStreamingServer[Req, Res] {
+	return &BidiStreamingServer[Req, Res]{BidiStream: newBidiStream(c, ctx, opts)}
+}
+
+// Recv 读取下一条请求消息，客户端发送关闭帧后返回 io.EOF
+func (s *BidiStreamingServer[Req, Res]) Recv() (*Req, error) {
+	m := new(Req)
+	if err := s.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Send 发送一条响应消息
+func (s *BidiStreamingServer[Req, Res]) Send(m *Res) error {
+	return s.SendMsg(m)
+}
@@ -0,0 +1,222 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type testBidiServer = BidiStreamingServer[wrapperspb.StringValue, wrapperspb.StringValue]
+
+// newBidiServer 启动以 handler 为双向流式方法实现的测试服务器，返回 WebSocket 地址
+func newBidiServer(t *testing.T, handler func(*testBidiServer) error, opts ...ServerOption) string {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	o := NewServerOptions(opts...)
+
+	r := gin.New()
+	r.GET("/ws", func(c *gin.Context) {
+		s := NewBidiStreamingServer[wrapperspb.StringValue, wrapperspb.StringValue](c, c.Request.Context(), o)
+		s.Finish(handler(s))
+	})
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws"
+}
+
+func dial(t *testing.T, url string) *websocket.Conn {
+	t.Helper()
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	return conn
+}
+
+// echo 原样返回收到的每条消息，客户端关闭后正常结束
+func echo(s *testBidiServer) error {
+	for {
+		m, err := s.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := s.Send(m); err != nil {
+			return err
+		}
+	}
+}
+
+// result 把流式方法实现的结果传给测试
+func result(ch chan<- error, f func(*testBidiServer) error) func(*testBidiServer) error {
+	return func(s *testBidiServer) error {
+		err := f(s)
+		ch <- err
+		return err
+	}
+}
+
+func wait(t *testing.T, ch <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-ch:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the stream to finish")
+		return nil
+	}
+}
+
+func TestBidiStreamFrameTypes(t *testing.T) {
+	conn := dial(t, newBidiServer(t, echo))
+
+	text := func(v string) []byte {
+		data, _ := protojson.Marshal(wrapperspb.String(v))
+		return data
+	}
+	binary := func(v string) []byte {
+		data, _ := proto.Marshal(wrapperspb.String(v))
+		return data
+	}
+	// 回复使用客户端最近一次发送的帧类型
+	steps := []struct {
+		typ  int
+		data []byte
+		want string
+	}{
+		{websocket.TextMessage, text("a"), "a"},
+		{websocket.BinaryMessage, binary("b"), "b"},
+		{websocket.BinaryMessage, binary("c"), "c"},
+		{websocket.TextMessage, text("d"), "d"},
+	}
+	for _, step := range steps {
+		if err := conn.WriteMessage(step.typ, step.data); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		typ, data, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		if typ != step.typ {
+			t.Fatalf("reply to %q: frame type = %d, want %d", step.want, typ, step.typ)
+		}
+
+		got := &wrapperspb.StringValue{}
+		if typ == websocket.BinaryMessage {
+			err = proto.Unmarshal(data, got)
+		} else {
+			err = protojson.Unmarshal(data, got)
+		}
+		if err != nil {
+			t.Fatalf("decode reply %q: %v", data, err)
+		}
+		if got.GetValue() != step.want {
+			t.Errorf("reply = %q, want %q", got.GetValue(), step.want)
+		}
+	}
+}
+
+func TestBidiStreamErrorCloseCode(t *testing.T) {
+	conn := dial(t, newBidiServer(t, func(s *testBidiServer) error {
+		if _, err := s.Recv(); err != nil {
+			return err
+		}
+		return status.Error(codes.NotFound, "user not found")
+	}))
+
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(`"x"`)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_, _, err := conn.ReadMessage()
+	var ce *websocket.CloseError
+	if !errors.As(err, &ce) {
+		t.Fatalf("read: %v, want close error", err)
+	}
+	if want := CloseCodeBase + int(codes.NotFound); ce.Code != want || ce.Text != "user not found" {
+		t.Errorf("close = %d %q, want %d %q", ce.Code, ce.Text, want, "user not found")
+	}
+}
+
+func TestBidiStreamClientClose(t *testing.T) {
+	done := make(chan error, 1)
+	conn := dial(t, newBidiServer(t, func(s *testBidiServer) error {
+		_, err := s.Recv()
+		done <- err
+		return nil
+	}))
+
+	msg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	if err := conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second)); err != nil {
+		t.Fatalf("write close: %v", err)
+	}
+	if err := wait(t, done); err != io.EOF {
+		t.Errorf("Recv after client close = %v, want io.EOF", err)
+	}
+
+	_, _, err := conn.ReadMessage()
+	if !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+		t.Errorf("read: %v, want normal closure", err)
+	}
+}
+
+func TestBidiStreamReadLimit(t *testing.T) {
+	done := make(chan error, 1)
+	conn := dial(t, newBidiServer(t, result(done, echo), WithMaxMessageSize(16)))
+
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(`"`+strings.Repeat("x", 64)+`"`)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := wait(t, done); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Recv = %v, want ResourceExhausted", err)
+	}
+}
+
+func TestBidiStreamKeepaliveTimeout(t *testing.T) {
+	done := make(chan error, 1)
+	url := newBidiServer(t, result(done, func(s *testBidiServer) error {
+		// 第一次 Recv 时升级连接
+		if _, err := s.Recv(); err != nil {
+			return err
+		}
+		select {
+		case <-s.Context().Done():
+			return context.Cause(s.Context())
+		case <-time.After(5 * time.Second):
+			return errors.New("context was not canceled")
+		}
+	}), WithPingInterval(20*time.Millisecond))
+
+	conn := dial(t, url)
+	// 不回复 pong
+	conn.SetPingHandler(func(string) error { return nil })
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(`"x"`)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	if err := wait(t, done); status.Code(err) != codes.Unavailable {
+		t.Errorf("context cause = %v, want Unavailable", err)
+	}
+}